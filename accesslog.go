@@ -0,0 +1,403 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccessLogFormat is the format of the access log lines AccessLog writes for
+// tools that don't read structured records.
+type AccessLogFormat int
+
+// Access log formats.
+const (
+	// AccessLogCommon is the Common Log Format, such as
+	//
+	//	127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326
+	AccessLogCommon AccessLogFormat = iota
+	// AccessLogCombined is the Common Log Format followed by the quoted Referer
+	// and User-Agent headers, as logged by Apache's combined format.
+	AccessLogCombined
+)
+
+// clfLayout is the layout of times in the Common Log Format.
+const clfLayout = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLogConfig configures AccessLog.
+type AccessLogConfig struct {
+	// Message is the message of access log records. Defaults to
+	// "http request".
+	Message string
+
+	// Route, if set, returns the pattern of the route that handled r, such as
+	// /users/{id}, which is logged as the route attribute so that records can
+	// be grouped by endpoint. It is called after the request is handled, once
+	// routers have matched it. With chi, use
+	//
+	//	func(r *http.Request) string { return chi.RouteContext(r.Context()).RoutePattern() }
+	//
+	// Routers that don't expose the route that way can call SetAccessLogRoute
+	// instead.
+	Route func(*http.Request) string
+
+	// Legacy, if set, is written a line in LegacyFormat for each request, in
+	// addition to the access log record, for tools parsing the access logs of
+	// web servers. Each line is written with a single call to Write.
+	Legacy io.Writer
+
+	// LegacyFormat is the format of the lines written to Legacy.
+	LegacyFormat AccessLogFormat
+
+	// OnError is called when writing to Legacy fails.
+	OnError func(error)
+
+	// Skip lists patterns of requests that aren't logged, such as health
+	// checks. Patterns have the form "[METHOD ]PATH", such as "/healthz" or
+	// "GET /metrics", where a * in PATH matches any characters, and are
+	// matched against both the path and the route of requests.
+	Skip []string
+
+	// Quiet lists patterns, like those of Skip, of requests logged at the debug
+	// level rather than the info level.
+	Quiet []string
+
+	// Sample maps patterns, like those of Skip, to the fraction of the matching
+	// requests that are logged, for high volume routes. Requests are kept
+	// evenly: with a fraction of 0.1, every tenth request is. If several
+	// patterns match a request, the longest is used.
+	//
+	// Failed requests, with a status of 400 or more, are always logged,
+	// regardless of Skip, Quiet and Sample, which also apply to Legacy.
+	Sample map[string]float64
+}
+
+// AccessLog returns middleware logging a record for each request once it is
+// handled, with its method, path, route, status, response size and duration,
+// and any error passed to SetAccessLogError. Records are logged at the info
+// level, or at the warn or error level for 4xx or 5xx responses, by the logger
+// carried by the request context, if any, so that with Middleware they are
+// correlated with the request's trace.
+//
+// The middleware has the signature used by most routers, so that it takes one
+// line to add it. Adapters for specific frameworks aren't provided, so that the
+// module doesn't depend on them. With chi, echo and gin respectively:
+//
+//	r.Use(l.AccessLog(cfg))
+//	e.Use(echo.WrapMiddleware(l.AccessLog(cfg)))
+//	http.ListenAndServe(addr, l.AccessLog(cfg)(engine))
+//
+// Frameworks reporting errors and routes through their own context can pass
+// them on from their error handler or a middleware, such as echo's
+// HTTPErrorHandler calling
+//
+//	logger.SetAccessLogError(c.Request().Context(), err)
+//
+// or a gin middleware calling, after c.Next,
+//
+//	logger.SetAccessLogRoute(c.Request.Context(), c.FullPath())
+func (l *L) AccessLog(cfg AccessLogConfig) func(http.Handler) http.Handler {
+	if cfg.Message == "" {
+		cfg.Message = "http request"
+	}
+
+	skip, quiet := parseAccessRules(cfg.Skip), parseAccessRules(cfg.Quiet)
+	samples := make([]accessRule, 0, len(cfg.Sample))
+	for pattern, rate := range cfg.Sample {
+		ar := parseAccessRule(pattern)
+		ar.rate = newSampleRate(rate)
+		samples = append(samples, ar)
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		if len(samples[i].pattern) != len(samples[j].pattern) {
+			return len(samples[i].pattern) > len(samples[j].pattern)
+		}
+		return samples[i].pattern < samples[j].pattern
+	})
+
+	var legacyMu sync.Mutex
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			st := &accessLogState{}
+			r = r.WithContext(context.WithValue(r.Context(), accessLogKey{}, st))
+			rec := &responseRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(rec, r)
+
+			if cfg.Route != nil {
+				if route := cfg.Route(r); route != "" {
+					st.setRoute(route)
+				}
+			}
+			route, err := st.get()
+
+			status := rec.statusCode()
+			lvl := slog.LevelInfo
+			switch {
+			case status >= 500:
+				lvl = slog.LevelError
+			case status >= 400:
+				lvl = slog.LevelWarn
+			default:
+				if matchAccessRule(skip, r, route) != nil {
+					return
+				}
+				if ar := matchAccessRule(samples, r, route); ar != nil && !ar.rate.keep() {
+					return
+				}
+				if matchAccessRule(quiet, r, route) != nil {
+					lvl = slog.LevelDebug
+				}
+			}
+
+			keyvals := []any{slog.String("method", r.Method), slog.String("path", r.URL.Path)}
+			if route != "" {
+				keyvals = append(keyvals, slog.String("route", route))
+			}
+			keyvals = append(keyvals,
+				slog.Int("status", status),
+				slog.Int64("size", rec.size),
+				slog.Duration("duration", time.Since(start)),
+			)
+			l.logAccess(r, lvl, cfg.Message, err, keyvals)
+
+			if cfg.Legacy != nil {
+				line := appendCLF(nil, r, rec, start, cfg.LegacyFormat)
+				legacyMu.Lock()
+				_, err := cfg.Legacy.Write(line)
+				legacyMu.Unlock()
+				if err != nil && cfg.OnError != nil {
+					cfg.OnError(err)
+				}
+			}
+		})
+	}
+}
+
+// logAccess logs the access log record of r.
+func (l *L) logAccess(r *http.Request, lvl slog.Level, msg string, err error, keyvals []any) {
+	rl := FromContext(r.Context())
+	if rl == nil {
+		rl = l
+	}
+	if !rl.traceForced(r.Context()) && !rl.enabled(r.Context(), lvl) {
+		return
+	}
+	if err != nil {
+		keyvals = rl.errorKeyvals(err, keyvals)
+	}
+
+	// The caller would be the middleware rather than anything of interest.
+	c := rl.clone()
+	c.showCaller = false
+	c.log(r.Context(), lvl, msg, keyvals...)
+}
+
+// accessRule matches requests by method and path, for AccessLogConfig.Skip,
+// Quiet and Sample.
+type accessRule struct {
+	pattern string
+	method  string // empty for any method
+	path    string
+	rate    *sampleRate
+}
+
+func parseAccessRule(pattern string) accessRule {
+	ar := accessRule{pattern: pattern, path: pattern}
+	if method, path, ok := strings.Cut(pattern, " "); ok {
+		ar.method, ar.path = method, strings.TrimSpace(path)
+	}
+	return ar
+}
+
+func parseAccessRules(patterns []string) []accessRule {
+	rules := make([]accessRule, 0, len(patterns))
+	for _, p := range patterns {
+		rules = append(rules, parseAccessRule(p))
+	}
+	return rules
+}
+
+// matchAccessRule returns the first of rules matching r, whose route is route
+// if it is known, or nil.
+func matchAccessRule(rules []accessRule, r *http.Request, route string) *accessRule {
+	for i, ar := range rules {
+		if ar.method != "" && ar.method != r.Method {
+			continue
+		}
+		if globMatch(ar.path, r.URL.Path) || (route != "" && globMatch(ar.path, route)) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// appendCLF appends the line of r in format f to b.
+func appendCLF(b []byte, r *http.Request, rec *responseRecorder, start time.Time, f AccessLogFormat) []byte {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	user, _, _ := r.BasicAuth()
+	uri := r.RequestURI
+	if uri == "" {
+		uri = r.URL.RequestURI()
+	}
+
+	b = appendCLFField(b, host)
+	b = append(b, " - "...)
+	b = appendCLFField(b, user)
+	b = append(b, " ["...)
+	b = start.AppendFormat(b, clfLayout)
+	b = append(b, "] \""...)
+	b = appendCLFEscaped(b, r.Method+" "+uri+" "+r.Proto)
+	b = append(b, "\" "...)
+	b = strconv.AppendInt(b, int64(rec.statusCode()), 10)
+	b = append(b, ' ')
+	if rec.size == 0 {
+		b = append(b, '-')
+	} else {
+		b = strconv.AppendInt(b, rec.size, 10)
+	}
+
+	if f == AccessLogCombined {
+		b = append(b, " \""...)
+		b = appendCLFField(b, r.Referer())
+		b = append(b, "\" \""...)
+		b = appendCLFField(b, r.UserAgent())
+		b = append(b, '"')
+	}
+	return append(b, '\n')
+}
+
+// appendCLFField appends a field, which is - if it is empty.
+func appendCLFField(b []byte, s string) []byte {
+	if s == "" {
+		return append(b, '-')
+	}
+	return appendCLFEscaped(b, s)
+}
+
+// appendCLFEscaped appends s, escaping quotes, backslashes and non-printable
+// bytes as Apache does, so that requests can't forge lines.
+func appendCLFEscaped(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			b = append(b, '\\', c)
+		case c < 0x20 || c >= 0x7f:
+			b = append(b, '\\', 'x', hex[c>>4], hex[c&0xf])
+		default:
+			b = append(b, c)
+		}
+	}
+	return b
+}
+
+type accessLogKey struct{}
+
+// accessLogState holds what handlers report to AccessLog about a request.
+type accessLogState struct {
+	mu    sync.Mutex
+	route string
+	err   error
+}
+
+func (s *accessLogState) setRoute(route string) {
+	s.mu.Lock()
+	s.route = route
+	s.mu.Unlock()
+}
+
+func (s *accessLogState) get() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.route, s.err
+}
+
+// SetAccessLogRoute sets the route attribute of the access log record of the
+// request ctx belongs to, for routers AccessLogConfig.Route can't query. It
+// does nothing if the request isn't handled by AccessLog.
+func SetAccessLogRoute(ctx context.Context, route string) {
+	if st, ok := ctx.Value(accessLogKey{}).(*accessLogState); ok {
+		st.setRoute(route)
+	}
+}
+
+// SetAccessLogError adds err to the access log record of the request ctx
+// belongs to, such as the error a framework's handler returned. It does
+// nothing if the request isn't handled by AccessLog.
+func SetAccessLogError(ctx context.Context, err error) {
+	if st, ok := ctx.Value(accessLogKey{}).(*accessLogState); ok {
+		st.mu.Lock()
+		st.err = err
+		st.mu.Unlock()
+	}
+}
+
+// responseRecorder records the status and size of a response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	// Informational responses precede the final one.
+	if w.status == 0 && status >= 200 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher, which handlers streaming responses check for.
+func (w *responseRecorder) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker, which websocket upgrades need. Hijacked
+// connections are logged with status 101 Switching Protocols unless the handler
+// wrote another status first.
+func (w *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// statusCode returns the status of the response, which is 200 OK if the
+// handler didn't write anything.
+func (w *responseRecorder) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
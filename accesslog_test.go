@@ -0,0 +1,293 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithName("app"),
+		WithKeyNames(KeyNames{Time: KeyOmit}),
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+		SetAccessLogRoute(r.Context(), "/users/{id}")
+		_, _ = io.WriteString(w, "hello")
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		SetAccessLogError(r.Context(), errors.New("database unavailable"))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {})
+	h := l.Middleware(l.AccessLog(AccessLogConfig{})(mux))
+
+	duration := regexp.MustCompile(` duration=[^ \n]+`)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/users/42", "level=info msg=\"http request\" src=app method=GET path=/users/42 route=/users/{id} status=200 size=5\n"},
+		{"/missing", "level=warn msg=\"http request\" src=app method=GET path=/missing status=404 size=19\n"},
+		{"/fail", "level=err msg=\"http request\" src=app method=GET path=/fail status=503 size=0 error=\"database unavailable\"\n"},
+		{"/empty", "level=info msg=\"http request\" src=app method=GET path=/empty status=200 size=0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			buf.Reset()
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Regexp(t, duration, buf.String())
+			require.Equal(t, tt.want, duration.ReplaceAllString(buf.String(), ""))
+		})
+	}
+
+	t.Run("request logger", func(t *testing.T) {
+		buf.Reset()
+		r := httptest.NewRequest(http.MethodGet, "/empty", nil)
+		r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		require.Contains(t, buf.String(), "trace_id=4bf92f3577b34da6a3ce929d0e0e4736")
+	})
+}
+
+func TestAccessLogConfig(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("warn"))
+
+	h := l.AccessLog(AccessLogConfig{
+		Message: "served",
+		Route:   func(r *http.Request) string { return "/things/{id}" },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/bad" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	require.Empty(t, buf.String())
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/bad", nil))
+	require.Contains(t, buf.String(), "level=warn msg=served")
+	require.Contains(t, buf.String(), "route=/things/{id} status=400")
+	require.NotContains(t, buf.String(), "caller=")
+}
+
+func TestAccessLogLegacy(t *testing.T) {
+	var structured, legacy bytes.Buffer
+	l := New(WithDestination(&structured), WithLevel("info"))
+
+	h := l.AccessLog(AccessLogConfig{Legacy: &legacy, LegacyFormat: AccessLogCombined})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "hello")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/hello?name=world", nil)
+	r.Header.Set("User-Agent", "curl/8.0")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	require.Contains(t, structured.String(), "path=/hello status=200")
+	require.Regexp(t, `^192\.0\.2\.1 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /hello\?name=world HTTP/1\.1" 200 5 "-" "curl/8\.0"\n$`, legacy.String())
+
+	t.Run("error", func(t *testing.T) {
+		var got error
+		h := l.AccessLog(AccessLogConfig{
+			Legacy:  errWriter{errors.New("disk full")},
+			OnError: func(err error) { got = err },
+		})(http.NotFoundHandler())
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		require.EqualError(t, got, "disk full")
+	})
+}
+
+func TestAccessLogSuppression(t *testing.T) {
+	var structured, legacy bytes.Buffer
+	l := New(WithDestination(&structured), WithLevel("info"))
+
+	h := l.AccessLog(AccessLogConfig{
+		Route:  func(r *http.Request) string { return r.Header.Get("Route") },
+		Legacy: &legacy,
+		Skip:   []string{"/healthz", "GET /metrics"},
+		Quiet:  []string{"/static/*"},
+		Sample: map[string]float64{"/api/*": 0.5, "/api/events/{id}": 0.25},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Fail") != "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+
+	count := func(method, path, route string, fail bool, n int) (int, int) {
+		structured.Reset()
+		legacy.Reset()
+		for i := 0; i < n; i++ {
+			r := httptest.NewRequest(method, path, nil)
+			r.Header.Set("Route", route)
+			if fail {
+				r.Header.Set("Fail", "1")
+			}
+			h.ServeHTTP(httptest.NewRecorder(), r)
+		}
+		return strings.Count(structured.String(), "\n"), strings.Count(legacy.String(), "\n")
+	}
+
+	tests := []struct {
+		name          string
+		method, path  string
+		route         string
+		fail          bool
+		logged, lines int
+	}{
+		{"skipped", http.MethodGet, "/healthz", "", false, 0, 0},
+		{"skipped failure", http.MethodGet, "/healthz", "", true, 8, 8},
+		{"skipped method", http.MethodGet, "/metrics", "", false, 0, 0},
+		{"other method", http.MethodPost, "/metrics", "", false, 8, 8},
+		{"quiet", http.MethodGet, "/static/app.js", "", false, 0, 8},
+		{"sampled path", http.MethodGet, "/api/users", "", false, 4, 4},
+		{"sampled route", http.MethodGet, "/api/events/1", "/api/events/{id}", false, 2, 2},
+		{"sampled failure", http.MethodGet, "/api/users", "", true, 8, 8},
+		{"unmatched", http.MethodGet, "/", "", false, 8, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logged, lines := count(tt.method, tt.path, tt.route, tt.fail, 8)
+			require.Equal(t, tt.logged, logged)
+			require.Equal(t, tt.lines, lines)
+		})
+	}
+
+	t.Run("quiet debug", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(WithDestination(&buf), WithLevel("debug"))
+		h := l.AccessLog(AccessLogConfig{Quiet: []string{"/healthz"}})(http.NotFoundHandler())
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		require.Contains(t, buf.String(), "level=warn")
+
+		h = l.AccessLog(AccessLogConfig{Quiet: []string{"/healthz"}})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		buf.Reset()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		require.Contains(t, buf.String(), "level=debug")
+	})
+}
+
+func TestAppendCLF(t *testing.T) {
+	start := time.Date(2000, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*60*60))
+
+	r := httptest.NewRequest(http.MethodGet, "/apache_pb.gif", nil)
+	r.Proto = "HTTP/1.0"
+	r.RemoteAddr = "127.0.0.1:51234"
+	r.SetBasicAuth("frank", "secret")
+	r.Header.Set("Referer", "http://www.example.com/start.html")
+	r.Header.Set("User-Agent", "Mozilla/4.08 [en] (Win98; I ;Nav)")
+	rec := &responseRecorder{status: http.StatusOK, size: 2326}
+
+	require.Equal(t,
+		`127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`+"\n",
+		string(appendCLF(nil, r, rec, start, AccessLogCommon)),
+	)
+	require.Equal(t,
+		`127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "http://www.example.com/start.html" "Mozilla/4.08 [en] (Win98; I ;Nav)"`+"\n",
+		string(appendCLF(nil, r, rec, start, AccessLogCombined)),
+	)
+
+	// Requests can't forge lines.
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "[::1]:51234"
+	r.RequestURI = "/\" 200 5\n"
+	r.Header.Set("User-Agent", "evil\\")
+	require.Equal(t,
+		`::1 - - [10/Oct/2000:13:55:36 -0700] "GET /\" 200 5\x0a HTTP/1.1" 204 - "-" "evil\\"`+"\n",
+		string(appendCLF(nil, r, &responseRecorder{status: http.StatusNoContent}, start, AccessLogCombined)),
+	)
+}
+
+type errWriter struct {
+	err error
+}
+
+func (w errWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func TestResponseRecorder(t *testing.T) {
+	rec := &responseRecorder{ResponseWriter: statusWriter{httptest.NewRecorder()}}
+	rec.WriteHeader(http.StatusEarlyHints)
+	require.Equal(t, http.StatusOK, rec.statusCode())
+	rec.WriteHeader(http.StatusNoContent)
+	require.Equal(t, http.StatusNoContent, rec.statusCode())
+
+	w := httptest.NewRecorder()
+	rec = &responseRecorder{ResponseWriter: w}
+	rec.WriteHeader(http.StatusCreated)
+	rec.WriteHeader(http.StatusInternalServerError)
+	rec.Flush()
+	_, err := rec.Write([]byte("abc"))
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusCreated, rec.statusCode())
+	require.EqualValues(t, 3, rec.size)
+	require.True(t, w.Flushed)
+	require.Equal(t, w, rec.Unwrap())
+
+	// Only requests handled by AccessLog are affected.
+	SetAccessLogRoute(httptest.NewRequest(http.MethodGet, "/", nil).Context(), "/")
+	SetAccessLogError(httptest.NewRequest(http.MethodGet, "/", nil).Context(), errors.New("ignored"))
+}
+
+// statusWriter discards informational responses, which httptest.ResponseRecorder
+// would take as final.
+type statusWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w statusWriter) WriteHeader(status int) {
+	if status >= 200 {
+		w.ResponseRecorder.WriteHeader(status)
+	}
+}
+
+func TestAccessLogHijack(t *testing.T) {
+	var buf syncBuffer
+	l := New(WithDestination(&buf), WithLevel("info"))
+
+	srv := httptest.NewServer(l.AccessLog(AccessLogConfig{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		_ = rw.Flush()
+	})))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "status=101")
+	}, time.Second, 5*time.Millisecond)
+	require.Contains(t, buf.String(), "level=info")
+}
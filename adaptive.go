@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// adaptiveMaxKeys bounds the number of distinct level and message pairs the
+// adaptive sampler tracks. Records beyond it are tracked by level alone.
+const adaptiveMaxKeys = 1000
+
+// adaptiveMaxSummaries is the number of summary records logged per interval.
+// Suppressed records of any remaining messages are summarized together.
+const adaptiveMaxSummaries = 10
+
+// sampleKey identifies records sampled together.
+type sampleKey struct {
+	level slog.Level
+	msg   string
+}
+
+// adaptiveSampler keeps the number of records logged each second within a
+// budget. It samples records with the same level and message at the same rate,
+// derived from how many of them were logged in the previous second, so that a
+// few noisy messages don't crowd out the rest.
+type adaptiveSampler struct {
+	budget       int
+	summaryEvery time.Duration
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowKept  int
+	seen        map[sampleKey]uint64
+	rates       map[sampleKey]float64
+	suppressed  map[sampleKey]uint64
+	lastSummary time.Time
+}
+
+func newAdaptiveSampler(perSecond int, summaryEvery time.Duration) *adaptiveSampler {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &adaptiveSampler{
+		budget:       perSecond,
+		summaryEvery: summaryEvery,
+		seen:         make(map[sampleKey]uint64),
+		suppressed:   make(map[sampleKey]uint64),
+	}
+}
+
+// sample reports whether a record logged at t should be kept, along with any
+// summary records that are due.
+func (s *adaptiveSampler) sample(t time.Time, lvl slog.Level, msg string) (bool, []slog.Record) {
+	if lvl >= slog.LevelWarn {
+		return true, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.windowStart.IsZero() {
+		s.windowStart, s.lastSummary = t, t
+	}
+	if t.Sub(s.windowStart) >= time.Second {
+		s.roll(t)
+	}
+
+	key := sampleKey{level: lvl, msg: msg}
+	if _, ok := s.seen[key]; !ok && len(s.seen) >= adaptiveMaxKeys {
+		key.msg = ""
+	}
+	s.seen[key]++
+	n := s.seen[key]
+
+	keep := s.windowKept < s.budget
+	if rate, ok := s.rates[key]; ok && keep {
+		keep = uint64(float64(n)*rate) != uint64(float64(n-1)*rate)
+	}
+	if keep {
+		s.windowKept++
+	} else if _, ok := s.suppressed[key]; ok || len(s.suppressed) < adaptiveMaxKeys {
+		s.suppressed[key]++
+	} else {
+		s.suppressed[sampleKey{level: lvl}]++
+	}
+
+	var summary []slog.Record
+	if s.summaryEvery > 0 && t.Sub(s.lastSummary) >= s.summaryEvery {
+		summary = s.summarize(t)
+		s.lastSummary = t
+	}
+	return keep, summary
+}
+
+// roll starts a new window at t, deriving the rate of each key from the number
+// of its records seen in the window that ended. The budget is shared out
+// evenly, with keys needing less than their share keeping everything and
+// leaving the rest to the others. Keys not in rates are kept in full.
+func (s *adaptiveSampler) roll(t time.Time) {
+	keys := make([]sampleKey, 0, len(s.seen))
+	for k := range s.seen {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return s.seen[keys[i]] < s.seen[keys[j]] })
+
+	s.rates = make(map[sampleKey]float64)
+	remaining := float64(s.budget)
+	for i, k := range keys {
+		share := remaining / float64(len(keys)-i)
+		seen := float64(s.seen[k])
+		if seen <= share {
+			remaining -= seen
+			continue
+		}
+		s.rates[k] = share / seen
+		remaining -= share
+	}
+
+	s.windowStart, s.windowKept = t, 0
+	s.seen = make(map[sampleKey]uint64, len(s.seen))
+}
+
+// summarize returns records describing the records suppressed since the last
+// summary, most suppressed first, and resets the counts.
+func (s *adaptiveSampler) summarize(t time.Time) []slog.Record {
+	if len(s.suppressed) == 0 {
+		return nil
+	}
+
+	keys := make([]sampleKey, 0, len(s.suppressed))
+	for k := range s.suppressed {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if s.suppressed[keys[i]] != s.suppressed[keys[j]] {
+			return s.suppressed[keys[i]] > s.suppressed[keys[j]]
+		}
+		if keys[i].level != keys[j].level {
+			return keys[i].level < keys[j].level
+		}
+		return keys[i].msg < keys[j].msg
+	})
+
+	var records []slog.Record
+	var others uint64
+	for i, k := range keys {
+		if i >= adaptiveMaxSummaries {
+			others += s.suppressed[k]
+			continue
+		}
+		r := slog.NewRecord(t, slog.LevelWarn, "records suppressed by sampling", 0)
+		r.AddAttrs(slog.String("sampled_level", levelLabel(k.level)))
+		if k.msg != "" {
+			r.AddAttrs(slog.String("sampled_msg", k.msg))
+		}
+		r.AddAttrs(slog.Uint64("suppressed", s.suppressed[k]))
+		records = append(records, r)
+	}
+	if others > 0 {
+		r := slog.NewRecord(t, slog.LevelWarn, "records suppressed by sampling", 0)
+		r.AddAttrs(slog.Uint64("suppressed", others))
+		records = append(records, r)
+	}
+
+	s.suppressed = make(map[sampleKey]uint64, len(s.suppressed))
+	return records
+}
+
+// adaptiveSample reports whether the adaptive sampler keeps a record at lvl
+// logged at t, logging any summaries that are due.
+func (l *L) adaptiveSample(ctx context.Context, t time.Time, lvl slog.Level, msg string) bool {
+	keep, summary := l.adaptive.sample(t, lvl, msg)
+	for _, r := range summary {
+		_ = l.slogger.Handler().Handle(ctx, r)
+	}
+	return keep
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerAdaptiveSampling(t *testing.T) {
+	var buf bytes.Buffer
+	ts := time.Unix(0, 0)
+	l := New(
+		WithDestination(&buf),
+		WithLevel("debug"),
+		WithCaller(false),
+		WithKeyNames(KeyNames{Time: KeyOmit}),
+		WithClock(func() time.Time { return ts }),
+		WithAdaptiveSampling(10, 2*time.Second),
+	)
+
+	second := func() {
+		l.Info("rare")
+		l.Info("rare")
+		for i := 0; i < 100; i++ {
+			l.Debug("noisy")
+		}
+		l.Warn("important")
+	}
+
+	// The first second is capped by the budget alone.
+	second()
+	require.Equal(t, 2, strings.Count(buf.String(), "msg=rare"))
+	require.Equal(t, 8, strings.Count(buf.String(), "msg=noisy"))
+	require.Equal(t, 1, strings.Count(buf.String(), "msg=important"))
+	buf.Reset()
+
+	// After that, rare messages keep their share and noisy ones are sampled.
+	ts = ts.Add(time.Second)
+	second()
+	require.Equal(t, 2, strings.Count(buf.String(), "msg=rare"))
+	require.Equal(t, 8, strings.Count(buf.String(), "msg=noisy"))
+	require.Equal(t, 1, strings.Count(buf.String(), "msg=important"))
+	buf.Reset()
+
+	ts = ts.Add(time.Second)
+	l.Info("rare")
+	require.Equal(t, `level=warn msg="records suppressed by sampling" src=go-logger.test sampled_level=debug sampled_msg=noisy suppressed=184
+level=info msg=rare src=go-logger.test
+`, buf.String())
+	require.Equal(t, uint64(184), l.Stats().Dropped)
+}
+
+func TestAdaptiveSamplerSummaryLimit(t *testing.T) {
+	s := newAdaptiveSampler(1, time.Second)
+	ts := time.Unix(0, 0)
+	for i := 0; i < adaptiveMaxSummaries+3; i++ {
+		s.sample(ts, LevelAll, strings.Repeat("x", i+1))
+	}
+
+	_, summary := s.sample(ts.Add(time.Second), LevelAll, "last")
+	require.Len(t, summary, adaptiveMaxSummaries+1)
+
+	var total uint64
+	for _, r := range summary {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == "suppressed" {
+				total += a.Value.Uint64()
+			}
+			return true
+		})
+	}
+	require.Equal(t, uint64(adaptiveMaxSummaries+2), total)
+	require.Nil(t, newAdaptiveSampler(0, time.Second))
+}
@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// config is the configuration a logger was created with, shared by all loggers
+// derived from it.
+type config struct {
+	format      string
+	level       slog.Level
+	destination string
+}
+
+func newConfig(opt *options) *config {
+	format := strings.ToLower(opt.format)
+	if format == "" {
+		format = FormatLogFmt
+	}
+	return &config{
+		format:      format,
+		level:       ParseLevel(opt.level).Level(),
+		destination: describeWriter(opt.destination),
+	}
+}
+
+// describeWriter returns a short description of a destination.
+func describeWriter(w io.Writer) string {
+	if f, ok := w.(*os.File); ok {
+		return f.Name()
+	}
+	return fmt.Sprintf("%T", w)
+}
+
+// AdminConfig is the configuration reported by the admin handler.
+type AdminConfig struct {
+	Format       string                 `json:"format"`
+	Level        string                 `json:"level"`
+	Destination  string                 `json:"destination"`
+	Destinations []AdminDestination     `json:"destinations"`
+	Loggers      []LoggerInfo           `json:"loggers,omitempty"`
+	Sampling     map[string]SampleStats `json:"sampling,omitempty"`
+	Stats        Stats                  `json:"stats"`
+}
+
+// AdminDestination is a writer the logger writes to.
+type AdminDestination struct {
+	Name   string `json:"name"`
+	Writer string `json:"writer"`
+}
+
+// AdminUpdate is the body of a PATCH request to the admin handler. Levels maps
+// logger names, as used by Registry, to their new level; a null level resets the
+// logger to inherit its level, or the root logger to the level it was created
+// with. Sampling maps level names to the fraction of their records to keep, as
+// passed to WithSampling; a null rate keeps all of them.
+type AdminUpdate struct {
+	Levels   map[string]*string  `json:"levels"`
+	Sampling map[string]*float64 `json:"sampling"`
+}
+
+// AdminHandler returns an http.Handler reporting the logger's configuration,
+// sampling, and Stats as JSON on GET. PATCH requests with an AdminUpdate body
+// change the level and sampling; the levels of sub-loggers can only be changed
+// if the logger uses a Registry. For a nil *L, the handler responds with 404 Not
+// Found.
+func (l *L) AdminHandler() http.Handler {
+	if l == nil {
+		return http.NotFoundHandler()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPatch:
+			if status, err := l.adminUpdate(r); err != nil {
+				http.Error(w, err.Error(), status)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PATCH")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(l.adminConfig())
+	})
+}
+
+func (l *L) adminConfig() AdminConfig {
+	cfg := AdminConfig{
+		Format:       l.config.format,
+		Level:        levelLabel(l.level.Level()),
+		Destination:  l.config.destination,
+		Destinations: make([]AdminDestination, 0, len(l.dests)),
+		Sampling:     l.sampler.stats(),
+		Stats:        l.Stats(),
+	}
+	for _, d := range l.dests {
+		cfg.Destinations = append(cfg.Destinations, AdminDestination{Name: d.name, Writer: describeWriter(d.w)})
+	}
+	if l.registry != nil {
+		cfg.Level = levelLabel(l.registry.Level(""))
+		cfg.Loggers = l.registry.Loggers()
+	}
+	return cfg
+}
+
+func (l *L) adminUpdate(r *http.Request) (int, error) {
+	var upd AdminUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("decoding body: %w", err)
+	}
+
+	for name, level := range upd.Levels {
+		if level != nil && !validLevel(*level) {
+			return http.StatusBadRequest, fmt.Errorf("invalid level %q for logger %q", *level, name)
+		}
+		if name != "" && l.registry == nil {
+			return http.StatusConflict, fmt.Errorf("level of logger %q can't be changed without a registry", name)
+		}
+	}
+	for level, rate := range upd.Sampling {
+		if !validLevel(level) {
+			return http.StatusBadRequest, fmt.Errorf("invalid sampling level %q", level)
+		}
+		if rate != nil && (*rate < 0 || *rate > 1) {
+			return http.StatusBadRequest, fmt.Errorf("invalid sampling rate %v for level %q", *rate, level)
+		}
+	}
+
+	for name, level := range upd.Levels {
+		switch {
+		case l.registry != nil && level == nil:
+			l.registry.ResetLevel(name)
+		case l.registry != nil:
+			l.registry.SetLevel(name, *level)
+		case level == nil:
+			l.level.Set(l.config.level)
+		default:
+			l.level.Set(ParseLevel(*level).Level())
+		}
+	}
+
+	if len(upd.Sampling) > 0 {
+		rates := make(map[string]float64, len(upd.Sampling))
+		for level, rate := range upd.Sampling {
+			rates[level] = -1
+			if rate != nil {
+				rates[level] = *rate
+			}
+		}
+		l.sampler.set(rates)
+	}
+	return 0, nil
+}
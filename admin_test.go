@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler(t *testing.T) {
+	reg := NewRegistry()
+	l := New(
+		WithDestination(io.Discard),
+		WithFormat(FormatJSON),
+		WithLevel("info"),
+		WithName("app"),
+		WithRegistry(reg),
+	)
+	l.New("db")
+
+	srv := httptest.NewServer(l.AdminHandler())
+	defer srv.Close()
+
+	get := func() AdminConfig {
+		resp, err := http.Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var cfg AdminConfig
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&cfg))
+		return cfg
+	}
+
+	patch := func(body string) *http.Response {
+		req, err := http.NewRequest(http.MethodPatch, srv.URL, strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	require.Equal(t, AdminConfig{
+		Format:       "json",
+		Level:        "info",
+		Destination:  "io.discard",
+		Destinations: []AdminDestination{{Name: "destination", Writer: "io.discard"}},
+		Loggers: []LoggerInfo{
+			{Name: "", Level: "info", Explicit: true},
+			{Name: "db", Level: "info"},
+		},
+		Stats: Stats{Records: map[string]uint64{"debug": 0, "info": 0, "warn": 0, "err": 0, "fatal": 0}},
+	}, get())
+
+	require.Equal(t, http.StatusOK, patch(`{"levels":{"db":"debug","":"warn"}}`).StatusCode)
+	cfg := get()
+	require.Equal(t, "warn", cfg.Level)
+	require.Equal(t, LoggerInfo{Name: "db", Level: "debug", Explicit: true}, cfg.Loggers[1])
+
+	require.Equal(t, http.StatusOK, patch(`{"levels":{"db":null}}`).StatusCode)
+	require.Equal(t, LoggerInfo{Name: "db", Level: "warn"}, get().Loggers[1])
+
+	require.Equal(t, http.StatusBadRequest, patch(`{"levels":{"db":"loud"}}`).StatusCode)
+	require.Equal(t, http.StatusBadRequest, patch(`{`).StatusCode)
+
+	resp, err := http.Post(srv.URL, "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestAdminHandlerWithoutRegistry(t *testing.T) {
+	var tee bytes.Buffer
+	l := New(
+		WithDestination(io.Discard),
+		WithLevel("warn"),
+		WithTee(&tee, FormatJSON),
+		WithSampling(map[string]float64{"info": 0.5}),
+		WithFilter(Not(MessageContains("noisy"))),
+	)
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		l.AdminHandler().ServeHTTP(rec, httptest.NewRequest(method, "/", strings.NewReader(body)))
+		return rec
+	}
+
+	l.Warn("noisy")
+	l.Warn("kept")
+	require.JSONEq(t, `{
+		"format": "logfmt",
+		"level": "warn",
+		"destination": "io.discard",
+		"destinations": [{"name": "destination", "writer": "io.discard"}, {"name": "tee", "writer": "*bytes.Buffer"}],
+		"sampling": {"info": {"rate": 0.5, "seen": 0, "kept": 0}},
+		"stats": {"records": {"debug": 0, "info": 0, "warn": 1, "err": 0, "fatal": 0}, "bytes_written": `+strconv.Itoa(int(l.Stats().BytesWritten))+`, "write_errors": 0, "dropped": 1}
+	}`, serve(http.MethodGet, "").Body.String())
+
+	// Runtime changes are reported.
+	o := l.TemporaryLevel("debug", time.Hour)
+	require.Contains(t, serve(http.MethodGet, "").Body.String(), `"level":"debug"`)
+	o.Revert()
+
+	rec := serve(http.MethodPatch, `{"levels":{"":"info"},"sampling":{"info":null,"debug":0.1}}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var cfg AdminConfig
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&cfg))
+	require.Equal(t, "info", cfg.Level)
+	require.Equal(t, map[string]SampleStats{"debug": {Rate: 0.1}}, cfg.Sampling)
+
+	tee.Reset()
+	for i := 0; i < 4; i++ {
+		l.Info("info")
+	}
+	require.Equal(t, 4, strings.Count(tee.String(), "\n"))
+
+	require.Equal(t, http.StatusOK, serve(http.MethodPatch, `{"levels":{"":null}}`).Code)
+	require.Equal(t, "warn", levelLabel(l.level.Level()))
+
+	require.Equal(t, http.StatusConflict, serve(http.MethodPatch, `{"levels":{"db":"debug"}}`).Code)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodPatch, `{"sampling":{"loud":0.5}}`).Code)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodPatch, `{"sampling":{"info":2}}`).Code)
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"log/slog"
+)
+
+// dedupeAttrs returns attrs with all but the last attribute for each key
+// removed, preserving the order of the remaining attributes.
+func dedupeAttrs(attrs []slog.Attr) []slog.Attr {
+	seen := make(map[string]bool, len(attrs))
+	deduped := make([]slog.Attr, len(attrs))
+	i := len(attrs)
+	for j := len(attrs) - 1; j >= 0; j-- {
+		if seen[attrs[j].Key] {
+			continue
+		}
+		seen[attrs[j].Key] = true
+		i--
+		deduped[i] = attrs[j]
+	}
+	return deduped[i:]
+}
+
+// dedupeRecord removes duplicate keys from r, along with any of the logger's
+// attributes that r overrides. It returns the handler that r should be passed
+// to, which doesn't include the overridden attributes.
+func (l *L) dedupeRecord(r slog.Record) (slog.Record, slog.Handler) {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	deduped := dedupeAttrs(attrs)
+	if len(deduped) != len(attrs) {
+		r = slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+		r.AddAttrs(deduped...)
+	}
+
+	keys := make(map[string]bool, len(deduped))
+	for _, a := range deduped {
+		keys[a.Key] = true
+	}
+
+	inherited := make([]slog.Attr, 0, len(l.attrs))
+	for _, a := range l.attrs {
+		if !keys[a.Key] {
+			inherited = append(inherited, a)
+		}
+	}
+
+	if len(inherited) == len(l.attrs) {
+		return r, l.slogger.Handler()
+	}
+	return r, withAttrs(l.base, inherited).Handler()
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupeAttrs(t *testing.T) {
+	tests := []struct {
+		desc     string
+		input    []slog.Attr
+		expected []slog.Attr
+	}{
+		{"empty", nil, []slog.Attr{}},
+		{
+			"unique",
+			[]slog.Attr{slog.String("a", "1"), slog.String("b", "2")},
+			[]slog.Attr{slog.String("a", "1"), slog.String("b", "2")},
+		},
+		{
+			"last wins",
+			[]slog.Attr{slog.String("a", "1"), slog.String("b", "2"), slog.String("a", "3")},
+			[]slog.Attr{slog.String("b", "2"), slog.String("a", "3")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			require.Equal(t, tt.expected, dedupeAttrs(tt.input))
+		})
+	}
+}
+
+func TestLoggerAttrDeduplication(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithName("app"),
+		WithLevel("info"),
+		WithFormat(FormatJSON),
+		WithAttrDeduplication(),
+		With("key1", "value1", "key2", "value2"),
+	)
+
+	l.New("sub").With("key1", "override1").Info("foo", "key2", "override2", "key3", "a", "key3", "b")
+
+	require.Equal(t, 1, strings.Count(buf.String(), `"src"`))
+	require.Equal(t, 1, strings.Count(buf.String(), `"key1"`))
+	require.Equal(t, 1, strings.Count(buf.String(), `"key2"`))
+	require.Equal(t, 1, strings.Count(buf.String(), `"key3"`))
+
+	var data map[string]string
+	require.NoError(t, json.NewDecoder(&buf).Decode(&data))
+	require.Equal(t, "app.sub", data["src"])
+	require.Equal(t, "override1", data["key1"])
+	require.Equal(t, "override2", data["key2"])
+	require.Equal(t, "b", data["key3"])
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+)
+
+// LevelAudit is the level of audit events logged via (*L).Audit. It is above
+// any level an application would define, and can't be registered with
+// RegisterLevel, so that audit events are never mistaken for other records.
+const LevelAudit = slog.Level(math.MaxInt32)
+
+// Audit logs an audit event recording that actor performed event on target.
+// Audit events are always logged regardless of the logger's level and are written
+// to the destination set by WithAuditDestination, if any. An error is returned if
+// any of event, actor, or target are empty, or if the event couldn't be written.
+func (l *L) Audit(event, actor, target string, keyvals ...any) error {
+	if l == nil {
+		return nil
+	}
+
+	var missing []string
+	for _, f := range []struct{ name, value string }{
+		{"event", event},
+		{"actor", actor},
+		{"target", target},
+	} {
+		if f.value == "" {
+			missing = append(missing, f.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("audit event missing required fields: %s", strings.Join(missing, ", "))
+	}
+
+	r := slog.NewRecord(l.now(), LevelAudit, event, 0)
+	r.AddAttrs(slog.String("actor", actor), slog.String("target", target))
+	r.Add(keyvals...)
+	if l.showCaller {
+		r.AddAttrs(l.callerAttrs(callerPC(2 + l.callerSkip))...)
+	}
+
+	l.sendToSinks(r)
+	return l.audit.Handler().Handle(context.Background(), r)
+}
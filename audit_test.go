@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit(t *testing.T) {
+	t.Run("bypasses level", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(
+			WithDestination(&buf),
+			WithName("app"),
+			WithLevel("fatal"),
+			With("key1", "value1"),
+		)
+
+		require.NoError(t, l.New("users").Audit("user_deleted", "admin", "user:42", "reason", "spam"))
+		require.Contains(t, buf.String(), "level=audit")
+		require.Contains(t, buf.String(), "msg=user_deleted")
+		require.Contains(t, buf.String(), "actor=admin")
+		require.Contains(t, buf.String(), "target=user:42")
+		require.Contains(t, buf.String(), "reason=spam")
+		require.Contains(t, buf.String(), "key1=value1")
+		require.Contains(t, buf.String(), "src=app.users")
+		require.Contains(t, buf.String(), "caller=github.com/jasonhancock/go-logger/audit_test.go")
+	})
+
+	t.Run("dedicated destination", func(t *testing.T) {
+		var buf, auditBuf bytes.Buffer
+		l := New(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithAuditDestination(&auditBuf),
+		)
+
+		l.Info("regular")
+		require.NoError(t, l.With("key1", "value1").Audit("login", "bob", "console"))
+		require.Contains(t, buf.String(), "msg=regular")
+		require.NotContains(t, buf.String(), "login")
+		require.Contains(t, auditBuf.String(), "msg=login")
+		require.Contains(t, auditBuf.String(), "key1=value1")
+		require.NotContains(t, auditBuf.String(), "regular")
+	})
+
+	t.Run("required fields", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(WithDestination(&buf))
+
+		err := l.Audit("login", "", "")
+		require.EqualError(t, err, "audit event missing required fields: actor, target")
+		require.Empty(t, buf.String())
+	})
+}
@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+)
+
+// hashKey is the attribute holding each audit event's chained hash.
+const hashKey = "hash"
+
+// chainStartKey marks the first audit event written by a process, whose hash
+// doesn't cover that of the event before it.
+const chainStartKey = "chain_start"
+
+// hashChainWriter appends a hash to each line written to w. The hash covers the
+// previous line's hash and the current line, so modifying, removing, or
+// reordering lines breaks the chain. The first line starts a new chain and is
+// marked as such, so that a process appending to the audit trail of a previous
+// one doesn't break its chain.
+type hashChainWriter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	json bool
+	key  []byte
+	prev []byte
+}
+
+func (w *hashChainWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	line := bytes.TrimSuffix(p, []byte("\n"))
+	if w.prev == nil {
+		line = markChainStart(line, w.json)
+	}
+	sum := chainSum(w.key, w.prev, line)
+
+	out := make([]byte, 0, len(line)+len(hashKey)+sha256.Size*2+8)
+	if w.json {
+		out = append(out, bytes.TrimSuffix(line, []byte("}"))...)
+		out = append(out, `,"`+hashKey+`":"`+hex.EncodeToString(sum)+`"}`...)
+	} else {
+		out = append(out, line...)
+		out = append(out, " "+hashKey+"="+hex.EncodeToString(sum)...)
+	}
+	out = append(out, '\n')
+
+	if _, err := w.w.Write(out); err != nil {
+		return 0, err
+	}
+	w.prev = sum
+
+	return len(p), nil
+}
+
+// markChainStart returns a copy of line with the chainStartKey attribute added.
+func markChainStart(line []byte, json bool) []byte {
+	out := make([]byte, 0, len(line)+len(chainStartKey)+10)
+	if json {
+		out = append(out, bytes.TrimSuffix(line, []byte("}"))...)
+		return append(out, `,"`+chainStartKey+`":true}`...)
+	}
+	out = append(out, line...)
+	return append(out, " "+chainStartKey+"=true"...)
+}
+
+// isChainStart reports whether line, without its hash, was marked by
+// markChainStart.
+func isChainStart(line []byte) bool {
+	return bytes.HasSuffix(line, []byte(" "+chainStartKey+"=true")) ||
+		bytes.HasSuffix(line, []byte(`,"`+chainStartKey+`":true}`))
+}
+
+// chainSum returns the hash of prev followed by line, using HMAC-SHA256 if key
+// is set and SHA-256 otherwise.
+func chainSum(key, prev, line []byte) []byte {
+	var h hash.Hash
+	if key != nil {
+		h = hmac.New(sha256.New, key)
+	} else {
+		h = sha256.New()
+	}
+	h.Write(prev)
+	h.Write(line)
+	return h.Sum(nil)
+}
+
+// VerifyAuditChain reads audit events written with WithAuditHashChain from r and
+// verifies that none of them have been modified, removed, or reordered. key must
+// match the key passed to WithAuditHashChain. Both logfmt and JSON output are
+// supported. Each process writing to the audit trail starts a new chain with a
+// marked event, which is covered by its hash, so audit trails appended to after
+// a restart verify too.
+func VerifyAuditChain(r io.Reader, key []byte) error {
+	var prev []byte
+
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1024*1024)
+	for n := 1; s.Scan(); n++ {
+		line, got, err := splitChainHash(s.Bytes())
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+
+		if isChainStart(line) {
+			prev = nil
+		}
+		want := chainSum(key, prev, line)
+		if !hmac.Equal(got, want) {
+			return fmt.Errorf("line %d: hash mismatch", n)
+		}
+		prev = want
+	}
+
+	return s.Err()
+}
+
+// splitChainHash separates a line written by hashChainWriter into its original
+// contents and hash.
+func splitChainHash(line []byte) ([]byte, []byte, error) {
+	var content []byte
+	var encoded []byte
+
+	if bytes.HasPrefix(line, []byte("{")) {
+		sep := []byte(`,"` + hashKey + `":"`)
+		i := bytes.LastIndex(line, sep)
+		if i == -1 || !bytes.HasSuffix(line, []byte(`"}`)) {
+			return nil, nil, fmt.Errorf("missing %s", hashKey)
+		}
+		content = append(line[:i:i], '}')
+		encoded = line[i+len(sep) : len(line)-2]
+	} else {
+		sep := []byte(" " + hashKey + "=")
+		i := bytes.LastIndex(line, sep)
+		if i == -1 {
+			return nil, nil, fmt.Errorf("missing %s", hashKey)
+		}
+		content = line[:i]
+		encoded = line[i+len(sep):]
+	}
+
+	sum, err := hex.DecodeString(string(encoded))
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding %s: %w", hashKey, err)
+	}
+
+	return content, sum, nil
+}
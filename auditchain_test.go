@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditHashChain(t *testing.T) {
+	tests := []struct {
+		desc   string
+		format string
+		key    []byte
+	}{
+		{"logfmt sha256", FormatLogFmt, nil},
+		{"logfmt hmac", FormatLogFmt, []byte("secret")},
+		{"json sha256", FormatJSON, nil},
+		{"json hmac", FormatJSON, []byte("secret")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var buf, auditBuf bytes.Buffer
+			l := New(
+				WithDestination(&buf),
+				WithFormat(tt.format),
+				WithAuditDestination(&auditBuf),
+				WithAuditHashChain(tt.key),
+			)
+
+			require.NoError(t, l.Audit("login", "alice", "console"))
+			require.NoError(t, l.Audit("user_deleted", "alice", "user:42"))
+			require.NoError(t, l.Audit("logout", "alice", "console"))
+			require.Equal(t, 3, strings.Count(auditBuf.String(), hashKey))
+			if tt.format == FormatJSON {
+				require.Contains(t, auditBuf.String(), `"}`+"\n")
+			}
+
+			require.NoError(t, VerifyAuditChain(strings.NewReader(auditBuf.String()), tt.key))
+
+			lines := strings.SplitAfter(auditBuf.String(), "\n")
+
+			t.Run("wrong key", func(t *testing.T) {
+				require.EqualError(t, VerifyAuditChain(strings.NewReader(auditBuf.String()), []byte("other")), "line 1: hash mismatch")
+			})
+
+			t.Run("modified", func(t *testing.T) {
+				tampered := strings.Replace(auditBuf.String(), "user:42", "user:43", 1)
+				require.EqualError(t, VerifyAuditChain(strings.NewReader(tampered), tt.key), "line 2: hash mismatch")
+			})
+
+			t.Run("removed", func(t *testing.T) {
+				tampered := lines[0] + lines[2]
+				require.EqualError(t, VerifyAuditChain(strings.NewReader(tampered), tt.key), "line 2: hash mismatch")
+			})
+
+			t.Run("missing hash", func(t *testing.T) {
+				tampered := lines[0] + "level=audit msg=forged\n"
+				require.EqualError(t, VerifyAuditChain(strings.NewReader(tampered), tt.key), "line 2: missing hash")
+			})
+
+			t.Run("appended after restart", func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "audit.log")
+				for _, actor := range []string{"alice", "bob"} {
+					f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+					require.NoError(t, err)
+					l := New(
+						WithDestination(io.Discard),
+						WithFormat(tt.format),
+						WithAuditDestination(f),
+						WithAuditHashChain(tt.key),
+					)
+					require.NoError(t, l.Audit("login", actor, "console"))
+					require.NoError(t, l.Audit("logout", actor, "console"))
+					require.NoError(t, f.Close())
+				}
+
+				b, err := os.ReadFile(path)
+				require.NoError(t, err)
+				require.Equal(t, 2, strings.Count(string(b), chainStartKey))
+				require.NoError(t, VerifyAuditChain(bytes.NewReader(b), tt.key))
+
+				lines := strings.SplitAfter(string(b), "\n")
+				require.EqualError(t, VerifyAuditChain(strings.NewReader(lines[1]+lines[2]+lines[3]), tt.key), "line 1: hash mismatch")
+				require.EqualError(t, VerifyAuditChain(strings.NewReader(lines[0]+lines[1]+lines[3]), tt.key), "line 3: hash mismatch")
+			})
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Azure Monitor HTTP Data Collector API limits. Posts may be up to 30MB; batches
+// are kept well below that to leave room for the JSON array framing.
+const (
+	azureMaxBatchBytes = 25 << 20
+	azureAPIVersion    = "2016-04-01"
+)
+
+// AzureMonitorConfig configures an AzureMonitorWriter.
+type AzureMonitorConfig struct {
+	// WorkspaceID is the Log Analytics workspace ID.
+	WorkspaceID string
+
+	// SharedKey is the base64 encoded primary or secondary key of the workspace.
+	SharedKey string
+
+	// LogType is the name of the custom log the records are stored in. Azure
+	// appends _CL to it.
+	LogType string
+
+	// TimeField is the record field holding the time the record was generated.
+	// Defaults to ts.
+	TimeField string
+
+	// Endpoint overrides the Data Collector endpoint, e.g. for sovereign clouds.
+	// Defaults to https://<WorkspaceID>.ods.opinsights.azure.com.
+	Endpoint string
+
+	// Client is the HTTP client used to post records. Defaults to
+	// http.DefaultClient.
+	Client *http.Client
+
+	// Interval is how often queued records are sent. Defaults to 5 seconds.
+	Interval time.Duration
+
+	// Retries is the number of times a failed batch is retried before it is
+	// dropped. Defaults to 3; a negative value disables retries.
+	Retries int
+
+	// OnError is called whenever a batch can't be delivered.
+	OnError func(error)
+}
+
+// AzureMonitorWriter is a destination that posts records in batches to the Azure
+// Monitor HTTP Data Collector API. Records should be written in FormatJSON so
+// that each attribute becomes a custom log column; nested groups are flattened
+// into columns joined with underscores. Records that aren't JSON are sent in a
+// single raw column.
+type AzureMonitorWriter struct {
+	batcher
+
+	cfg AzureMonitorConfig
+	key []byte
+	url string
+}
+
+// NewAzureMonitorWriter returns an AzureMonitorWriter. Close must be called to
+// send any records still queued.
+func NewAzureMonitorWriter(cfg AzureMonitorConfig) (*AzureMonitorWriter, error) {
+	key, err := base64.StdEncoding.DecodeString(cfg.SharedKey)
+	if err != nil {
+		return nil, fmt.Errorf("decoding shared key: %w", err)
+	}
+	if cfg.WorkspaceID == "" || cfg.LogType == "" {
+		return nil, errors.New("workspace ID and log type are required")
+	}
+	if cfg.TimeField == "" {
+		cfg.TimeField = KeyNames{}.withDefaults().Time
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://" + cfg.WorkspaceID + ".ods.opinsights.azure.com"
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.Retries == 0 {
+		cfg.Retries = 3
+	}
+
+	w := &AzureMonitorWriter{
+		cfg: cfg,
+		key: key,
+		url: strings.TrimSuffix(cfg.Endpoint, "/") + "/api/logs?api-version=" + azureAPIVersion,
+	}
+	w.batcher = batcher{
+		send:     w.send,
+		interval: cfg.Interval,
+		maxBytes: azureMaxBatchBytes,
+		overhead: 1,
+		retries:  cfg.Retries,
+		onError:  cfg.OnError,
+	}
+	w.start()
+	return w, nil
+}
+
+func (w *AzureMonitorWriter) send(ctx context.Context, batch []batchRecord) error {
+	rows := make([]map[string]any, len(batch))
+	for i, r := range batch {
+		rows[i] = azureColumns(r.data)
+	}
+
+	body, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+
+	date := time.Now().UTC().Format(http.TimeFormat)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Log-Type", w.cfg.LogType)
+	req.Header.Set("x-ms-date", date)
+	req.Header.Set("time-generated-field", w.cfg.TimeField)
+	req.Header.Set("Authorization", "SharedKey "+w.cfg.WorkspaceID+":"+w.signature(len(body), date))
+
+	resp, err := w.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("azure monitor: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// signature computes the SharedKey authorization signature for a post of the
+// given length.
+func (w *AzureMonitorWriter) signature(length int, date string) string {
+	msg := "POST\n" + strconv.Itoa(length) + "\napplication/json\nx-ms-date:" + date + "\n/api/logs"
+	mac := hmac.New(sha256.New, w.key)
+	mac.Write([]byte(msg))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// azureColumns maps a record to custom log columns.
+func azureColumns(data []byte) map[string]any {
+	var rec map[string]any
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return map[string]any{"raw": string(data)}
+	}
+
+	cols := make(map[string]any, len(rec))
+	flattenColumns(cols, "", rec)
+	return cols
+}
+
+func flattenColumns(cols map[string]any, prefix string, m map[string]any) {
+	for k, v := range m {
+		name := azureColumnName(k)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+		if group, ok := v.(map[string]any); ok {
+			flattenColumns(cols, name, group)
+			continue
+		}
+		cols[name] = v
+	}
+}
+
+// azureColumnName replaces characters that aren't allowed in column names.
+func azureColumnName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, s)
+}
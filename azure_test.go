@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureMonitorWriter(t *testing.T) {
+	key := []byte("secret")
+	sharedKey := base64.StdEncoding.EncodeToString(key)
+
+	var mu sync.Mutex
+	var rows []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		require.Equal(t, "/api/logs", r.URL.Path)
+		require.Equal(t, "2016-04-01", r.URL.Query().Get("api-version"))
+		require.Equal(t, "App", r.Header.Get("Log-Type"))
+		require.Equal(t, "ts", r.Header.Get("time-generated-field"))
+
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte("POST\n" + strconv.Itoa(len(body)) + "\napplication/json\nx-ms-date:" + r.Header.Get("x-ms-date") + "\n/api/logs"))
+		require.Equal(t, "SharedKey ws:"+base64.StdEncoding.EncodeToString(mac.Sum(nil)), r.Header.Get("Authorization"))
+
+		var batch []map[string]any
+		require.NoError(t, json.Unmarshal(body, &batch))
+		mu.Lock()
+		rows = append(rows, batch...)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	w, err := NewAzureMonitorWriter(AzureMonitorConfig{
+		WorkspaceID: "ws",
+		SharedKey:   sharedKey,
+		LogType:     "App",
+		Endpoint:    srv.URL,
+		Interval:    time.Hour,
+	})
+	require.NoError(t, err)
+
+	l := New(WithDestination(w), WithFormat(FormatJSON), WithLevel("info"), WithCaller(false))
+	l.Info("foo", "http", map[string]any{"status-code": 200})
+	_, err = w.Write([]byte("not json\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	require.Len(t, rows, 2)
+	require.Equal(t, "foo", rows[0]["msg"])
+	require.Equal(t, float64(200), rows[0]["http_status_code"])
+	require.Equal(t, map[string]any{"raw": "not json"}, rows[1])
+}
+
+func TestAzureMonitorWriterError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad key", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	w, err := NewAzureMonitorWriter(AzureMonitorConfig{
+		WorkspaceID: "ws",
+		LogType:     "App",
+		Endpoint:    srv.URL,
+		Interval:    time.Hour,
+		Retries:     -1,
+	})
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("{}\n"))
+	require.NoError(t, err)
+	require.EqualError(t, w.Close(), "azure monitor: 403 Forbidden: bad key")
+
+	_, err = NewAzureMonitorWriter(AzureMonitorConfig{WorkspaceID: "ws", LogType: "App", SharedKey: "!"})
+	require.ErrorContains(t, err, "decoding shared key")
+}
@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// batchRecord is a single record queued for a batching destination.
+type batchRecord struct {
+	time time.Time
+	data []byte
+}
+
+// batcher queues records written to it and hands them to send in batches, either
+// when the interval elapses or when a batch reaches its size limits. Sending
+// happens in the background so logging never blocks on the network. Failed
+// batches are retried with exponential backoff before being dropped.
+type batcher struct {
+	send     func(context.Context, []batchRecord) error
+	interval time.Duration
+	maxCount int
+	maxBytes int
+	overhead int
+	maxSpan  time.Duration
+	retries  int
+	backoff  time.Duration
+	onError  func(error)
+
+	mu      sync.Mutex
+	cur     []batchRecord
+	size    int
+	ready   [][]batchRecord
+	closed  bool
+	lastErr error
+
+	sendMu sync.Mutex
+	kick   chan struct{}
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func (b *batcher) start() {
+	if b.interval <= 0 {
+		b.interval = 5 * time.Second
+	}
+	if b.backoff <= 0 {
+		b.backoff = 100 * time.Millisecond
+	}
+	b.kick = make(chan struct{}, 1)
+	b.done = make(chan struct{})
+
+	b.wg.Add(1)
+	go b.run()
+}
+
+// Write queues p as a single record. A trailing newline is removed.
+func (b *batcher) Write(p []byte) (int, error) {
+	rec := batchRecord{
+		time: time.Now(),
+		data: bytes.Clone(bytes.TrimSuffix(p, []byte("\n"))),
+	}
+	n := len(rec.data) + b.overhead
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return 0, os.ErrClosed
+	}
+
+	full := len(b.cur) > 0 &&
+		((b.maxCount > 0 && len(b.cur) >= b.maxCount) ||
+			(b.maxBytes > 0 && b.size+n > b.maxBytes) ||
+			(b.maxSpan > 0 && rec.time.Sub(b.cur[0].time) > b.maxSpan))
+	if full {
+		b.cut()
+		select {
+		case b.kick <- struct{}{}:
+		default:
+		}
+	}
+
+	b.cur = append(b.cur, rec)
+	b.size += n
+	return len(p), nil
+}
+
+// cut moves the current batch to the ready queue. b.mu must be held.
+func (b *batcher) cut() {
+	if len(b.cur) == 0 {
+		return
+	}
+	b.ready = append(b.ready, b.cur)
+	b.cur = nil
+	b.size = 0
+}
+
+// Flush sends everything queued so far.
+func (b *batcher) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	b.cut()
+	b.mu.Unlock()
+	return b.sendReady(ctx)
+}
+
+// Health returns the error of the last attempt to send in the background, if it
+// failed, along with the number of records waiting to be sent.
+func (b *batcher) Health() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.lastErr == nil {
+		return nil
+	}
+	n := len(b.cur)
+	for _, batch := range b.ready {
+		n += len(batch)
+	}
+	return fmt.Errorf("%d records queued, last send failed: %w", n, b.lastErr)
+}
+
+// Close stops the background sender and sends everything queued so far.
+func (b *batcher) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.cut()
+	b.mu.Unlock()
+
+	close(b.done)
+	b.wg.Wait()
+
+	return b.sendReady(context.Background())
+}
+
+func (b *batcher) run() {
+	defer b.wg.Done()
+
+	t := time.NewTicker(b.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-t.C:
+			b.mu.Lock()
+			b.cut()
+			b.mu.Unlock()
+		case <-b.kick:
+		}
+
+		err := b.sendReady(context.Background())
+		if err != nil && b.onError != nil {
+			b.onError(err)
+		}
+	}
+}
+
+func (b *batcher) sendReady(ctx context.Context) error {
+	b.sendMu.Lock()
+	defer b.sendMu.Unlock()
+
+	b.mu.Lock()
+	ready := b.ready
+	b.ready = nil
+	b.mu.Unlock()
+
+	if len(ready) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, batch := range ready {
+		errs = append(errs, b.sendBatch(ctx, batch))
+	}
+	err := errors.Join(errs...)
+
+	// Only record the outcome of actual sends, so that a failure is reported by
+	// Health until a later batch is sent successfully.
+	b.mu.Lock()
+	b.lastErr = err
+	b.mu.Unlock()
+	return err
+}
+
+func (b *batcher) sendBatch(ctx context.Context, batch []batchRecord) error {
+	delay := b.backoff
+	for attempt := 0; ; attempt++ {
+		err := b.send(ctx, batch)
+		if err == nil || attempt >= b.retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func BenchmarkCaller(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = callerFile(callerFrame(callerPC(1)), "github.com/jasonhancock/go-logger/")
+	}
+}
+
+func BenchmarkLogger(b *testing.B) {
+	benchmarks := []struct {
+		desc string
+		opts []Option
+		fn   func(l *L)
+	}{
+		{
+			"disabled",
+			nil,
+			func(l *L) { l.Debug("some message", "key1", "value1") },
+		},
+		{
+			"info",
+			nil,
+			func(l *L) { l.Info("some message", "key1", "value1") },
+		},
+		{
+			"info without caller",
+			[]Option{WithCaller(false)},
+			func(l *L) { l.Info("some message", "key1", "value1") },
+		},
+		{
+			"info json",
+			[]Option{WithFormat(FormatJSON)},
+			func(l *L) { l.Info("some message", "key1", "value1") },
+		},
+		{
+			"info json-pretty",
+			[]Option{WithFormat(FormatJSONPretty)},
+			func(l *L) { l.Info("some message", "key1", "value1") },
+		},
+		{
+			"info key order",
+			[]Option{WithKeyOrder("msg", "level")},
+			func(l *L) { l.Info("some message", "key1", "value1") },
+		},
+		{
+			"info color",
+			[]Option{WithColor(ColorAlways)},
+			func(l *L) { l.Info("some message", "key1", "value1") },
+		},
+		{
+			"info max record size",
+			[]Option{WithFormat(FormatJSON), WithMaxRecordSize(1024)},
+			func(l *L) { l.Info("some message", "key1", "value1") },
+		},
+		{
+			"info many attrs",
+			nil,
+			func(l *L) {
+				l.Info("some message", "key1", "value1", "key2", 2, "key3", true, "key4", 4.0, "key5", "value5")
+			},
+		},
+		{
+			"With",
+			nil,
+			func(l *L) {
+				_ = l.With("key1", "value1", "key2", 2, "key3", true, "key4", 4.0, "key5", "value5", "key6", 6)
+			},
+		},
+		{
+			"LogError disabled",
+			[]Option{WithLevel("fatal")},
+			func(l *L) { l.LogError("some message", errors.New("some error")) },
+		},
+		{
+			"LogError",
+			nil,
+			func(l *L) { l.LogError("some message", errors.New("some error")) },
+		},
+	}
+
+	for _, bb := range benchmarks {
+		b.Run(bb.desc, func(b *testing.B) {
+			l := New(append([]Option{
+				WithDestination(io.Discard),
+				WithLevel("info"),
+				With("key0", "value0"),
+			}, bb.opts...)...)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				bb.fn(l)
+			}
+		})
+	}
+}
+
+// BenchmarkSlog measures direct slog usage as a baseline for BenchmarkLogger.
+func BenchmarkSlog(b *testing.B) {
+	l := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo})).
+		With("key0", "value0")
+
+	b.Run("disabled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Debug("some message", "key1", "value1")
+		}
+	})
+
+	b.Run("info", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info("some message", "key1", "value1")
+		}
+	})
+}
+
+// BenchmarkSilence compares a silenced logger to one formatting records before
+// discarding them.
+func BenchmarkSilence(b *testing.B) {
+	loggers := []struct {
+		desc string
+		l    *L
+	}{
+		{"Silence", Silence()},
+		{"discard destination", New(WithDestination(io.Discard), WithLevel("info"))},
+	}
+
+	for _, ll := range loggers {
+		b.Run(ll.desc+"/info", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ll.l.Info("some message", "key1", "value1")
+			}
+		})
+
+		b.Run(ll.desc+"/LogError", func(b *testing.B) {
+			err := errors.New("some error")
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ll.l.LogError("some message", err)
+			}
+		})
+	}
+}
+
+// TestLoggerAllocs guards the allocation targets of the hot paths measured by
+// BenchmarkLogger.
+func TestLoggerAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not reliable with the race detector")
+	}
+
+	tests := []struct {
+		desc   string
+		opts   []Option
+		allocs float64
+	}{
+		{"logfmt", nil, 0},
+		{"json", []Option{WithFormat(FormatJSON)}, 0},
+		{"json-pretty", []Option{WithFormat(FormatJSONPretty)}, 0},
+		{"json max record size", []Option{WithFormat(FormatJSON), WithMaxRecordSize(1024)}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			l := New(append([]Option{
+				WithDestination(io.Discard),
+				WithLevel("info"),
+				With("key0", "value0"),
+			}, tt.opts...)...)
+
+			allocs := testing.AllocsPerRun(100, func() {
+				l.Info("some message", "key1", "value1")
+			})
+			require.LessOrEqual(t, allocs, tt.allocs)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"sync"
+)
+
+// captureMaxLine is the longest line of subprocess output logged by CaptureCmd.
+// Longer lines are truncated.
+const captureMaxLine = 8192
+
+// CaptureCmd sets cmd's stdout and stderr to log each line the command writes,
+// tagged with a cmd attribute holding the command's name and a stream attribute
+// of stdout or stderr. Lines written to stdout are logged at level and lines
+// written to stderr at the warning level, or at level if it is higher. Lines
+// longer than 8KiB are truncated and marked with truncated=true. Blank lines
+// are skipped.
+//
+// CaptureCmd must be called before the command is started. The returned
+// function logs any final output not terminated by a newline and should be
+// called once cmd.Wait or cmd.Run returns.
+func (l *L) CaptureCmd(cmd *exec.Cmd, level string) (flush func()) {
+	if l == nil {
+		return func() {}
+	}
+
+	lvl := ParseLevel(level).Level()
+	errLvl := max(lvl, slog.LevelWarn)
+
+	// The caller of the records would be in os/exec's copying goroutine rather
+	// than anywhere useful, so leave it out.
+	cl := l.With("cmd", filepath.Base(cmd.Path))
+	cl.showCaller = false
+
+	stdout := &lineWriter{l: cl, lvl: lvl, stream: "stdout"}
+	stderr := &lineWriter{l: cl, lvl: errLvl, stream: "stderr"}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	return func() {
+		stdout.flush()
+		stderr.flush()
+	}
+}
+
+// lineWriter logs each line written to it.
+type lineWriter struct {
+	l      *L
+	lvl    slog.Level
+	stream string
+
+	mu        sync.Mutex
+	buf       []byte
+	truncated bool
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		chunk := p
+		if i >= 0 {
+			chunk = p[:i]
+		}
+
+		if room := captureMaxLine - len(w.buf); len(chunk) > room {
+			chunk = chunk[:room]
+			w.truncated = true
+		}
+		w.buf = append(w.buf, chunk...)
+
+		if i < 0 {
+			break
+		}
+		w.logLine()
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.logLine()
+}
+
+// logLine logs the buffered line and resets the buffer. w.mu must be held.
+func (w *lineWriter) logLine() {
+	line := bytes.TrimSuffix(w.buf, []byte("\r"))
+	if len(bytes.TrimSpace(line)) > 0 {
+		keyvals := []any{"stream", w.stream}
+		if w.truncated {
+			keyvals = append(keyvals, "truncated", true)
+		}
+		w.l.log(context.Background(), w.lvl, string(line), keyvals...)
+	}
+	w.buf = w.buf[:0]
+	w.truncated = false
+}
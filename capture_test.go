@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerCaptureCmd(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	var buf syncBuffer
+	l := New(WithDestination(&buf), WithName("app"), WithLevel("debug"), WithClock(func() time.Time { return time.Unix(0, 0) }))
+
+	long := strings.Repeat("x", captureMaxLine+10)
+	cmd := exec.Command("sh", "-c", "echo hello; echo; echo oops >&2; echo "+long+"; printf partial")
+	flush := l.CaptureCmd(cmd, "debug")
+	require.NoError(t, cmd.Run())
+	flush()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines, `ts=1970-01-01T00:00:00.000Z level=debug msg=hello src=app cmd=sh stream=stdout`)
+	require.Contains(t, lines, `ts=1970-01-01T00:00:00.000Z level=warn msg=oops src=app cmd=sh stream=stderr`)
+	require.Contains(t, lines, `ts=1970-01-01T00:00:00.000Z level=debug msg=`+long[:captureMaxLine]+` src=app cmd=sh stream=stdout truncated=true`)
+	require.Equal(t, `ts=1970-01-01T00:00:00.000Z level=debug msg=partial src=app cmd=sh stream=stdout`, lines[3])
+}
+
+func TestLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithName("app"), WithLevel("info"), WithCaller(false), WithClock(func() time.Time { return time.Unix(0, 0) }))
+	w := &lineWriter{l: l, lvl: slog.LevelInfo, stream: "stdout"}
+
+	_, err := w.Write([]byte("one\r\ntw"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("o\n"))
+	require.NoError(t, err)
+	w.flush()
+
+	require.Equal(t, "ts=1970-01-01T00:00:00.000Z level=info msg=one src=app stream=stdout\nts=1970-01-01T00:00:00.000Z level=info msg=two src=app stream=stdout\n", buf.String())
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// CardinalityAction is what the cardinality guard does with new values of an
+// attribute once it has seen too many distinct ones.
+type CardinalityAction int
+
+// Cardinality guard actions.
+const (
+	// CardinalityBucket replaces new values with one of as many buckets as the
+	// limit, chosen by hashing the value, such as bucket-17, so that the
+	// attribute keeps at most twice the limit distinct values.
+	CardinalityBucket CardinalityAction = iota
+	// CardinalityDrop removes the attribute from records with new values.
+	CardinalityDrop
+)
+
+// cardinalityMaxKeys bounds the number of attributes the guard tracks when
+// guarding every attribute. Attributes beyond it are not guarded.
+const cardinalityMaxKeys = 1000
+
+// cardinalityGuard tracks the distinct values of attributes.
+type cardinalityGuard struct {
+	limit   int
+	action  CardinalityAction
+	keys    []string // the attributes guarded, or nil for all but exempt
+	exempt  []string
+	onLimit func(error)
+
+	tracked atomic.Int64
+	states  sync.Map // key => *cardinalityState
+}
+
+// cardinalityState holds the values seen for an attribute.
+type cardinalityState struct {
+	mu       sync.Mutex
+	values   map[string]struct{}
+	exceeded atomic.Bool
+}
+
+// guardCardinality returns a replaceAttrFunc applying the guard to top level
+// attributes, or nil if limit isn't positive. onLimit, if not nil, is called
+// the first time each attribute exceeds the limit.
+func guardCardinality(limit int, action CardinalityAction, keys, exempt []string, onLimit func(error)) replaceAttrFunc {
+	if limit <= 0 {
+		return nil
+	}
+
+	g := &cardinalityGuard{limit: limit, action: action, keys: keys, exempt: exempt, onLimit: onLimit}
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 || a.Value.Kind() == slog.KindGroup || !g.guarded(a.Key) {
+			return a
+		}
+		return g.check(a)
+	}
+}
+
+func (g *cardinalityGuard) guarded(key string) bool {
+	if g.keys != nil {
+		return slices.Contains(g.keys, key)
+	}
+	return !slices.Contains(g.exempt, key)
+}
+
+// check returns a unchanged if its value has been seen before or the limit
+// hasn't been reached, and as changed by the action otherwise.
+func (g *cardinalityGuard) check(a slog.Attr) slog.Attr {
+	st := g.state(a.Key)
+	if st == nil {
+		return a
+	}
+
+	v := a.Value.Resolve().String()
+	st.mu.Lock()
+	_, seen := st.values[v]
+	if !seen && len(st.values) < g.limit {
+		st.values[v] = struct{}{}
+		seen = true
+	}
+	st.mu.Unlock()
+	if seen {
+		return a
+	}
+
+	if !st.exceeded.Swap(true) && g.onLimit != nil {
+		g.onLimit(fmt.Errorf("logger: attribute %q exceeded %d distinct values", a.Key, g.limit))
+	}
+	if g.action == CardinalityDrop {
+		return slog.Attr{}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(v))
+	return slog.String(a.Key, "bucket-"+strconv.Itoa(int(h.Sum32()%uint32(g.limit))))
+}
+
+// state returns the values seen for key, or nil if too many attributes are
+// already tracked.
+func (g *cardinalityGuard) state(key string) *cardinalityState {
+	if st, ok := g.states.Load(key); ok {
+		return st.(*cardinalityState)
+	}
+	if g.keys == nil && g.tracked.Load() >= cardinalityMaxKeys {
+		return nil
+	}
+
+	st, loaded := g.states.LoadOrStore(key, &cardinalityState{values: make(map[string]struct{})})
+	if !loaded {
+		g.tracked.Add(1)
+	}
+	return st.(*cardinalityState)
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerCardinalityLimit(t *testing.T) {
+	t.Run("bucket", func(t *testing.T) {
+		var buf bytes.Buffer
+		var errs []error
+		l := New(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithCaller(false),
+			WithKeyNames(KeyNames{Time: KeyOmit}),
+			WithErrorHandler(func(err error) { errs = append(errs, err) }),
+			WithCardinalityLimit(2, CardinalityBucket, "user_id"),
+		)
+
+		for _, id := range []string{"a", "b", "a", "c", "d"} {
+			l.Info("request", "user_id", id, "path", id)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Equal(t, []string{
+			"level=info msg=request src=go-logger.test user_id=a path=a",
+			"level=info msg=request src=go-logger.test user_id=b path=b",
+			"level=info msg=request src=go-logger.test user_id=a path=a",
+		}, lines[:3])
+		require.Regexp(t, `^level=info msg=request src=go-logger.test user_id=bucket-[01] path=c$`, lines[3])
+		require.Regexp(t, `^level=info msg=request src=go-logger.test user_id=bucket-[01] path=d$`, lines[4])
+		require.Len(t, errs, 1)
+		require.EqualError(t, errs[0], `logger: attribute "user_id" exceeded 2 distinct values`)
+	})
+
+	t.Run("drop all", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithKeyNames(KeyNames{Time: KeyOmit}),
+			WithRecordIDs(),
+			WithCardinalityLimit(1, CardinalityDrop),
+		)
+
+		l.Info("one", "id", 1, "group", slog.GroupValue(slog.Int("n", 1)))
+		l.With("id", 2).Info("two", "group", slog.GroupValue(slog.Int("n", 2)))
+		l.Info("three", "id", 1)
+
+		out := buf.String()
+		require.Contains(t, out, "msg=one src=go-logger.test id=1 group.n=1")
+		require.Contains(t, out, "msg=two src=go-logger.test group.n=2")
+		require.Contains(t, out, "msg=three src=go-logger.test id=1")
+		// The logger's own attributes are never guarded.
+		require.Equal(t, 3, strings.Count(out, "record_id="))
+		require.Equal(t, 3, strings.Count(out, "caller="))
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		fn := guardCardinality(100, CardinalityBucket, nil, nil, nil)
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					fn(nil, slog.String("k", fmt.Sprint(i, j)))
+				}
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	require.Nil(t, guardCardinality(0, CardinalityDrop, nil, nil, nil))
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// DefaultCloudEventsType is the CloudEvents type used for log records when
+// none is set with WithCloudEventsType.
+const DefaultCloudEventsType = "com.github.jasonhancock.go-logger.record"
+
+// cloudEvent is a CloudEvents v1.0 envelope in the structured JSON encoding.
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Time            string          `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// cloudEventsWriter wraps each JSON record written to it in a CloudEvents
+// envelope before writing it to w. The record itself becomes the event data.
+type cloudEventsWriter struct {
+	w         io.Writer
+	typ       string
+	sourceKey string
+	timeKey   string
+}
+
+func (w *cloudEventsWriter) Write(p []byte) (int, error) {
+	data := bytes.TrimSpace(p)
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return w.w.Write(p)
+	}
+
+	ev := cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              newEventID(),
+		Source:          "/",
+		Type:            w.typ,
+		DataContentType: "application/json",
+		Data:            data,
+	}
+
+	var s string
+	if json.Unmarshal(fields[w.sourceKey], &s) == nil && s != "" {
+		ev.Source = s
+	}
+	// The time attribute is optional but must be RFC 3339 when present, so it is
+	// omitted if the record's timestamp has been reformatted to something else.
+	if json.Unmarshal(fields[w.timeKey], &s) == nil {
+		if _, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			ev.Time = s
+		}
+	}
+
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return w.w.Write(p)
+	}
+
+	if _, err := w.w.Write(append(b, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// newEventID returns a random identifier for a CloudEvent.
+func newEventID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerCloudEvents(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithFormat(FormatCloudEvents),
+		WithName("app"),
+		WithCaller(false),
+	)
+
+	l.New("worker").Info("foo", "key1", "value1")
+	l.Info("bar")
+
+	type event struct {
+		SpecVersion     string            `json:"specversion"`
+		ID              string            `json:"id"`
+		Source          string            `json:"source"`
+		Type            string            `json:"type"`
+		Time            string            `json:"time"`
+		DataContentType string            `json:"datacontenttype"`
+		Data            map[string]string `json:"data"`
+	}
+
+	dec := json.NewDecoder(&buf)
+
+	var ev1, ev2 event
+	require.NoError(t, dec.Decode(&ev1))
+	require.NoError(t, dec.Decode(&ev2))
+
+	require.Equal(t, "1.0", ev1.SpecVersion)
+	require.Len(t, ev1.ID, 32)
+	require.NotEqual(t, ev1.ID, ev2.ID)
+	require.Equal(t, "app.worker", ev1.Source)
+	require.Equal(t, "app", ev2.Source)
+	require.Equal(t, DefaultCloudEventsType, ev1.Type)
+	require.Equal(t, "application/json", ev1.DataContentType)
+	require.Equal(t, ev1.Time, ev1.Data["ts"])
+	_, err := time.Parse(time.RFC3339Nano, ev1.Time)
+	require.NoError(t, err)
+	require.Equal(t, "foo", ev1.Data["msg"])
+	require.Equal(t, "value1", ev1.Data["key1"])
+	require.Equal(t, "bar", ev2.Data["msg"])
+}
+
+func TestLoggerCloudEventsType(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithFormat(FormatCloudEvents),
+		WithCloudEventsType("com.example.log"),
+		WithTimeFormat(TimeFormatUnix),
+		WithCaller(false),
+	)
+
+	l.Info("foo")
+
+	var ev map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &ev))
+	require.Equal(t, "com.example.log", ev["type"])
+	require.NotContains(t, ev, "time")
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CloudWatch Logs PutLogEvents limits.
+const (
+	cloudWatchMaxEvents     = 10000
+	cloudWatchMaxBatchBytes = 1048576
+	cloudWatchMaxEventBytes = 256 * 1024
+	cloudWatchEventOverhead = 26
+	cloudWatchMaxSpan       = 24 * time.Hour
+)
+
+// CloudWatchEvent is a single log event sent to CloudWatch Logs.
+type CloudWatchEvent struct {
+	Timestamp time.Time
+	Message   string
+}
+
+// CloudWatchClient puts a batch of events into a CloudWatch Logs stream. It is
+// typically a thin adapter around the PutLogEvents call of an AWS SDK client. It
+// returns the sequence token to use for the next call, if any. When the service
+// rejects the sequence token, the client should return a
+// *CloudWatchSequenceTokenError holding the expected token.
+type CloudWatchClient interface {
+	PutLogEvents(ctx context.Context, group, stream string, events []CloudWatchEvent, sequenceToken string) (string, error)
+}
+
+// CloudWatchSequenceTokenError reports that a PutLogEvents call was made with an
+// invalid sequence token.
+type CloudWatchSequenceTokenError struct {
+	ExpectedSequenceToken string
+}
+
+func (e *CloudWatchSequenceTokenError) Error() string {
+	return fmt.Sprintf("invalid sequence token, expected %q", e.ExpectedSequenceToken)
+}
+
+// CloudWatchConfig configures a CloudWatchWriter.
+type CloudWatchConfig struct {
+	Group  string
+	Stream string
+
+	// Interval is how often queued events are sent. Defaults to 5 seconds.
+	Interval time.Duration
+
+	// Retries is the number of times a failed batch is retried before it is
+	// dropped. Defaults to 3; a negative value disables retries.
+	Retries int
+
+	// OnError is called whenever a batch can't be delivered.
+	OnError func(error)
+}
+
+// CloudWatchWriter is a destination that sends each record as an event to a
+// CloudWatch Logs stream, batching them within the service's limits.
+type CloudWatchWriter struct {
+	batcher
+
+	client CloudWatchClient
+	group  string
+	stream string
+	token  string
+}
+
+// NewCloudWatchWriter returns a CloudWatchWriter sending events with client.
+// Close must be called to send any events still queued.
+func NewCloudWatchWriter(client CloudWatchClient, cfg CloudWatchConfig) *CloudWatchWriter {
+	if cfg.Retries == 0 {
+		cfg.Retries = 3
+	}
+
+	w := &CloudWatchWriter{
+		client: client,
+		group:  cfg.Group,
+		stream: cfg.Stream,
+	}
+	w.batcher = batcher{
+		send:     w.send,
+		interval: cfg.Interval,
+		maxCount: cloudWatchMaxEvents,
+		maxBytes: cloudWatchMaxBatchBytes,
+		overhead: cloudWatchEventOverhead,
+		maxSpan:  cloudWatchMaxSpan,
+		retries:  cfg.Retries,
+		onError:  cfg.OnError,
+	}
+	w.start()
+	return w
+}
+
+func (w *CloudWatchWriter) send(ctx context.Context, batch []batchRecord) error {
+	events := make([]CloudWatchEvent, len(batch))
+	for i, r := range batch {
+		events[i] = CloudWatchEvent{
+			Timestamp: r.time,
+			// Leave room for the suffix truncate adds.
+			Message: truncate(string(r.data), cloudWatchMaxEventBytes-cloudWatchEventOverhead-32),
+		}
+	}
+
+	token, err := w.client.PutLogEvents(ctx, w.group, w.stream, events, w.token)
+	var seqErr *CloudWatchSequenceTokenError
+	if errors.As(err, &seqErr) {
+		token, err = w.client.PutLogEvents(ctx, w.group, w.stream, events, seqErr.ExpectedSequenceToken)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.token = token
+	return nil
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCloudWatch struct {
+	mu      sync.Mutex
+	token   string
+	batches [][]CloudWatchEvent
+	fail    int
+}
+
+func (c *fakeCloudWatch) PutLogEvents(ctx context.Context, group, stream string, events []CloudWatchEvent, token string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fail > 0 {
+		c.fail--
+		return "", errors.New("throttled")
+	}
+	if token != c.token {
+		return "", &CloudWatchSequenceTokenError{ExpectedSequenceToken: c.token}
+	}
+
+	c.batches = append(c.batches, events)
+	c.token = strings.Repeat("t", len(c.batches))
+	return c.token, nil
+}
+
+func TestCloudWatchWriter(t *testing.T) {
+	client := &fakeCloudWatch{token: "initial"}
+	w := NewCloudWatchWriter(client, CloudWatchConfig{
+		Group:    "group",
+		Stream:   "stream",
+		Interval: time.Hour,
+	})
+	w.backoff = time.Millisecond
+	client.fail = 2
+
+	l := New(WithDestination(w), WithLevel("info"), WithCaller(false))
+	l.Info("foo")
+	l.Info("bar")
+	require.NoError(t, w.Flush(context.Background()))
+
+	l.Info("baz")
+	require.NoError(t, w.Close())
+
+	require.Len(t, client.batches, 2)
+	require.Len(t, client.batches[0], 2)
+	require.Contains(t, client.batches[0][0].Message, "msg=foo")
+	require.False(t, strings.HasSuffix(client.batches[0][0].Message, "\n"))
+	require.Contains(t, client.batches[1][0].Message, "msg=baz")
+	require.Equal(t, "tt", client.token)
+}
+
+func TestCloudWatchWriterLimits(t *testing.T) {
+	client := &fakeCloudWatch{}
+	w := NewCloudWatchWriter(client, CloudWatchConfig{Interval: time.Hour})
+
+	big := strings.Repeat("x", 300*1024)
+	for i := 0; i < 5; i++ {
+		_, err := w.Write([]byte(big + "\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	var total int
+	for _, batch := range client.batches {
+		size := 0
+		for _, ev := range batch {
+			require.LessOrEqual(t, len(ev.Message)+cloudWatchEventOverhead, cloudWatchMaxEventBytes)
+			size += len(ev.Message) + cloudWatchEventOverhead
+		}
+		require.LessOrEqual(t, size, cloudWatchMaxBatchBytes)
+		total += len(batch)
+	}
+	require.Equal(t, 5, total)
+	require.Greater(t, len(client.batches), 1)
+}
+
+func TestCloudWatchWriterDropsAfterRetries(t *testing.T) {
+	client := &fakeCloudWatch{fail: 10}
+	w := NewCloudWatchWriter(client, CloudWatchConfig{Interval: time.Hour, Retries: 1})
+	w.backoff = time.Millisecond
+
+	_, err := w.Write([]byte("foo\n"))
+	require.NoError(t, err)
+	require.EqualError(t, w.Close(), "throttled")
+	require.Empty(t, client.batches)
+}
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// ColorMode specifies whether levels are colored in logfmt output.
+type ColorMode int
+
+// Color modes.
+const (
+	// ColorNever disables coloring.
+	ColorNever ColorMode = iota
+	// ColorAuto colors output when the destination is a terminal, unless the
+	// NO_COLOR environment variable is set or TERM is dumb.
+	ColorAuto
+	// ColorAlways colors output regardless of the destination.
+	ColorAlways
+)
+
+// enabled reports whether output to w should be colored.
+func (m ColorMode) enabled(w io.Writer) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorAuto:
+		if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+			return false
+		}
+		return isTerminal(w)
+	default:
+		return false
+	}
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+const colorReset = "\x1b[0m"
+
+var levelColors = map[string]string{
+	"debug": "\x1b[90m",
+	"info":  "\x1b[32m",
+	"warn":  "\x1b[33m",
+	"audit": "\x1b[35m",
+	"err":   "\x1b[31m",
+	"fatal": "\x1b[1;31m",
+}
+
+// colorWriter colors the level of each logfmt record written to it, and the
+// message of error and fatal records, before writing it to w.
+type colorWriter struct {
+	w          io.Writer
+	levelKey   string
+	messageKey string
+}
+
+func (w *colorWriter) Write(p []byte) (int, error) {
+	line := bytes.TrimSuffix(p, []byte("\n"))
+	fields := splitLogfmt(line)
+	if fields == nil {
+		return w.w.Write(p)
+	}
+
+	var color string
+	for _, f := range fields {
+		if logfmtKey(f) == w.levelKey {
+			color = levelColors[string(logfmtValue(f))]
+			break
+		}
+	}
+	if color == "" {
+		return w.w.Write(p)
+	}
+	highlight := color == levelColors["err"] || color == levelColors["fatal"]
+
+	out := make([]byte, 0, len(p)+32)
+	for i, f := range fields {
+		if i > 0 {
+			out = append(out, ' ')
+		}
+		key := logfmtKey(f)
+		if key != w.levelKey && (!highlight || key != w.messageKey) {
+			out = append(out, f...)
+			continue
+		}
+		v := logfmtValue(f)
+		out = append(out, f[:len(f)-len(v)]...)
+		out = append(out, color...)
+		out = append(out, v...)
+		out = append(out, colorReset...)
+	}
+	out = append(out, '\n')
+
+	if _, err := w.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// logfmtValue returns the raw value of a key=value field.
+func logfmtValue(field []byte) []byte {
+	end, _ := scanLogfmtToken(field, 0, '=')
+	return field[end+1:]
+}
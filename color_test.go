@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerColor(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("debug"),
+		WithCaller(false),
+		WithKeyNames(KeyNames{Time: KeyOmit}),
+		WithColor(ColorAlways),
+	)
+
+	l.Debug("d")
+	l.Info("i")
+	l.Err("oh no", "msg2", "x")
+
+	require.Equal(t, "level=\x1b[90mdebug\x1b[0m msg=d src=go-logger.test\n"+
+		"level=\x1b[32minfo\x1b[0m msg=i src=go-logger.test\n"+
+		"level=\x1b[31merr\x1b[0m msg=\x1b[31m\"oh no\"\x1b[0m src=go-logger.test msg2=x\n",
+		buf.String())
+}
+
+func TestColorModeEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	require.False(t, ColorNever.enabled(os.Stdout))
+	require.True(t, ColorAlways.enabled(&buf))
+	require.False(t, ColorAuto.enabled(&buf))
+
+	f, err := os.CreateTemp(t.TempDir(), "log")
+	require.NoError(t, err)
+	defer f.Close()
+	require.False(t, ColorAuto.enabled(f))
+
+	t.Setenv("NO_COLOR", "1")
+	require.False(t, ColorAuto.enabled(os.Stdout))
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Compression algorithms.
+const (
+	CompressionGzip = "gzip"
+)
+
+// errWriterClosed is returned when writing to a compressing writer after the
+// logger has been closed.
+var errWriterClosed = errors.New("logger: write to closed compressed destination")
+
+// newCompressWriter returns a writer compressing its output to w using the
+// named algorithm.
+func newCompressWriter(alg string, w io.Writer) (*compressWriter, error) {
+	switch strings.ToLower(alg) {
+	case CompressionGzip:
+		return &compressWriter{zw: gzip.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("logger: unsupported compression %q", alg)
+	}
+}
+
+// compressWriter streams records through a compressor. The main and audit
+// handlers can share a destination, so writes are serialized here.
+type compressWriter struct {
+	mu     sync.Mutex
+	zw     *gzip.Writer
+	closed bool
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, errWriterClosed
+	}
+	return w.zw.Write(p)
+}
+
+// Close flushes any buffered output and writes the compression trailer. The
+// underlying destination is not closed.
+func (w *compressWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.zw.Close()
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerCompression(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithCompression(CompressionGzip),
+		WithCaller(false),
+	)
+
+	l.Info("foo", "key1", "value1")
+	l.New("sub").Info("bar")
+	require.NoError(t, l.Close())
+	require.NoError(t, l.Close())
+
+	zr, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	data, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "msg=foo src=go-logger.test key1=value1\n")
+	require.Contains(t, string(data), "msg=bar ")
+
+	var reported []error
+	l = New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithCompression(CompressionGzip),
+		WithErrorHandler(func(err error) { reported = append(reported, err) }),
+	)
+	require.NoError(t, l.Close())
+	l.Info("dropped")
+	require.Equal(t, []error{errWriterClosed}, reported)
+}
+
+func TestLoggerCompressionUnsupported(t *testing.T) {
+	var buf bytes.Buffer
+	var reported error
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithCompression("zstd"),
+		WithErrorHandler(func(err error) { reported = err }),
+		WithCaller(false),
+	)
+
+	require.EqualError(t, reported, `logger: unsupported compression "zstd"`)
+	l.Info("foo")
+	require.NoError(t, l.Close())
+	require.Contains(t, buf.String(), "msg=foo ")
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// DebugIf logs a message at the debug level if cond is true.
+func (l *L) DebugIf(cond bool, msg any, keyvals ...any) {
+	if cond {
+		l.log(context.Background(), slog.LevelDebug, msg, keyvals...)
+	}
+}
+
+// InfoIf logs a message at the info level if cond is true.
+func (l *L) InfoIf(cond bool, msg any, keyvals ...any) {
+	if cond {
+		l.log(context.Background(), slog.LevelInfo, msg, keyvals...)
+	}
+}
+
+// WarnIf logs a message at the warning level if cond is true.
+func (l *L) WarnIf(cond bool, msg any, keyvals ...any) {
+	if cond {
+		l.log(context.Background(), slog.LevelWarn, msg, keyvals...)
+	}
+}
+
+// ErrIf logs a message at the error level if cond is true.
+func (l *L) ErrIf(cond bool, msg any, keyvals ...any) {
+	if cond {
+		l.log(context.Background(), slog.LevelError, msg, keyvals...)
+	}
+}
+
+// LogIfError logs err as LogError does if it is non-nil, and returns it
+// unchanged, so that
+//
+//	if err := save(); err != nil {
+//		l.LogError("save failed", err)
+//		return err
+//	}
+//
+// can be written as
+//
+//	return l.LogIfError(save(), "save failed")
+func (l *L) LogIfError(err error, msg string, keyvals ...any) error {
+	if err == nil || !l.enabled(context.Background(), slog.LevelError) {
+		return err
+	}
+	l.log(context.Background(), slog.LevelError, msg, l.errorKeyvals(err, keyvals)...)
+	return err
+}
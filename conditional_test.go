@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerConditional(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("debug"), WithCaller(false), WithKeyNames(KeyNames{Time: KeyOmit}))
+
+	t.Run("if", func(t *testing.T) {
+		defer buf.Reset()
+
+		for _, cond := range []bool{false, true} {
+			l.DebugIf(cond, "debug", "cond", cond)
+			l.InfoIf(cond, "info", "cond", cond)
+			l.WarnIf(cond, "warn", "cond", cond)
+			l.ErrIf(cond, "err", "cond", cond)
+		}
+		require.Equal(t, `level=debug msg=debug src=go-logger.test cond=true
+level=info msg=info src=go-logger.test cond=true
+level=warn msg=warn src=go-logger.test cond=true
+level=err msg=err src=go-logger.test cond=true
+`, buf.String())
+	})
+
+	t.Run("LogIfError", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, l.LogIfError(nil, "save failed"))
+		require.Empty(t, buf.String())
+
+		err := errors.New("disk full")
+		require.Same(t, err, l.LogIfError(err, "save failed", "id", 1))
+		require.Equal(t, `level=err msg="save failed" src=go-logger.test id=1 error="disk full"
+`, buf.String())
+	})
+
+	t.Run("caller", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(WithDestination(&buf), WithLevel("info"))
+
+		l.ErrIf(true, "foo")
+		_, _, line, _ := runtime.Caller(0)
+		require.Contains(t, buf.String(), fmt.Sprintf("caller=github.com/jasonhancock/go-logger/conditional_test.go:%d", line-1))
+		buf.Reset()
+
+		_ = l.LogIfError(errors.New("bar"), "foo")
+		_, _, line, _ = runtime.Caller(0)
+		require.Contains(t, buf.String(), fmt.Sprintf("caller=github.com/jasonhancock/go-logger/conditional_test.go:%d", line-1))
+	})
+
+	t.Run("nil logger", func(t *testing.T) {
+		var l *L
+		l.ErrIf(true, "foo")
+		err := errors.New("bar")
+		require.Same(t, err, l.LogIfError(err, "foo"))
+	})
+}
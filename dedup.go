@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// repeatedKey is the attribute carrying the number of suppressed duplicates.
+const repeatedKey = "repeated"
+
+// dedupState is shared between a dedupHandler and all handlers derived from it.
+type dedupState struct {
+	mu      sync.Mutex
+	window  time.Duration
+	hash    uint64
+	start   time.Time
+	count   int
+	last    slog.Record
+	handler slog.Handler
+	timer   *time.Timer
+	stats   *stats
+	ignore  []string // attributes excluded when comparing records
+}
+
+// dedupHandler suppresses consecutive identical records logged within a window.
+// The first record is logged immediately. Once the run of duplicates ends, a
+// single summary record, the last duplicate, is logged with a repeated attribute
+// holding the number of duplicates.
+type dedupHandler struct {
+	inner slog.Handler
+	state *dedupState
+	scope uint64 // identifies the attributes and groups added to inner
+}
+
+func newDedupHandler(inner slog.Handler, window time.Duration, st *stats, ignore ...string) *dedupHandler {
+	return &dedupHandler{inner: inner, state: &dedupState{window: window, stats: st, ignore: ignore}}
+}
+
+func (h *dedupHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.inner.Enabled(ctx, lvl)
+}
+
+func (h *dedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	sum := h.hash(r)
+
+	s := h.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sum == s.hash && r.Time.Sub(s.start) < s.window {
+		s.count++
+		s.last = r.Clone()
+		s.handler = h.inner
+		if s.timer == nil {
+			s.timer = time.AfterFunc(s.window-r.Time.Sub(s.start), s.expire)
+		}
+		return nil
+	}
+
+	if err := s.flush(ctx); err != nil {
+		return err
+	}
+
+	s.hash, s.start = sum, r.Time
+	return h.inner.Handle(ctx, r)
+}
+
+// hash identifies records with the same level, message, attributes, and
+// handler attributes and groups.
+func (h *dedupHandler) hash(r slog.Record) uint64 {
+	f := fnv.New64a()
+	f.Write(strconv.AppendUint(nil, h.scope, 10))
+	f.Write([]byte(r.Level.String()))
+	f.Write([]byte(r.Message))
+	r.Attrs(func(a slog.Attr) bool {
+		if slices.Contains(h.state.ignore, a.Key) {
+			return true
+		}
+		f.Write([]byte(a.Key))
+		f.Write([]byte(a.Value.Resolve().String()))
+		return true
+	})
+	return f.Sum64()
+}
+
+// flush logs the summary of the pending run of duplicates, if any. The caller
+// must hold s.mu.
+func (s *dedupState) flush(ctx context.Context) error {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	if s.count == 0 {
+		return nil
+	}
+
+	r, h := s.last, s.handler
+	r.AddAttrs(slog.Int(repeatedKey, s.count))
+	s.stats.drop(s.count - 1)
+	s.count, s.hash, s.last, s.handler = 0, 0, slog.Record{}, nil
+	return h.Handle(ctx, r)
+}
+
+// expire flushes the pending run once the window has elapsed.
+func (s *dedupState) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.timer = nil
+	_ = s.flush(context.Background())
+}
+
+// Close logs the summary of the pending run of duplicates, if any.
+func (s *dedupState) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.flush(context.Background())
+}
+
+// WithAttrs and WithGroup derive the scope from the attributes and groups rather
+// than the call, so that loggers derived alike, such as per-request loggers,
+// share it.
+func (h *dedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	f := fnv.New64a()
+	f.Write(strconv.AppendUint(nil, h.scope, 10))
+	for _, a := range attrs {
+		f.Write([]byte{0})
+		f.Write([]byte(a.Key))
+		f.Write([]byte{0})
+		f.Write([]byte(a.Value.Resolve().String()))
+	}
+	return &dedupHandler{inner: h.inner.WithAttrs(attrs), state: h.state, scope: f.Sum64()}
+}
+
+func (h *dedupHandler) WithGroup(name string) slog.Handler {
+	f := fnv.New64a()
+	f.Write(strconv.AppendUint(nil, h.scope, 10))
+	f.Write([]byte{1})
+	f.Write([]byte(name))
+	return &dedupHandler{inner: h.inner.WithGroup(name), state: h.state, scope: f.Sum64()}
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerDeduplication(t *testing.T) {
+	var buf syncBuffer
+	now := time.Now()
+	var mu sync.Mutex
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithName("app"),
+		WithCaller(false),
+		WithDeduplication(time.Hour),
+		WithClock(func() time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return now
+		}),
+	)
+
+	for i := 0; i < 5; i++ {
+		l.Info("retrying", "attempt", "same")
+	}
+	require.Equal(t, 1, strings.Count(buf.String(), "msg=retrying"))
+
+	// Loggers derived alike share a scope.
+	for i := 0; i < 3; i++ {
+		l.New("sub").With("request", "same").Info("retrying", "attempt", "same")
+	}
+	require.NoError(t, l.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[1], "msg=retrying src=app attempt=same repeated=4")
+	require.Contains(t, lines[2], "src=app.sub request=same")
+	require.NotContains(t, lines[2], "repeated")
+	require.Contains(t, lines[3], "src=app.sub request=same")
+	require.Contains(t, lines[3], "repeated=2")
+
+	t.Run("unique", func(t *testing.T) {
+		buf.Reset()
+
+		// Records that aren't repeated aren't held back.
+		l.Info("foo")
+		require.Contains(t, buf.String(), "msg=foo")
+		l.Info("bar")
+		require.Contains(t, buf.String(), "msg=bar")
+		require.NoError(t, l.Close())
+		require.Equal(t, 1, strings.Count(buf.String(), "msg=foo"))
+		require.NotContains(t, buf.String(), "repeated")
+	})
+
+	t.Run("different scope", func(t *testing.T) {
+		buf.Reset()
+
+		l.With("request", "1").Info("foo")
+		l.With("request", "2").Info("foo")
+		require.NoError(t, l.Close())
+		require.Equal(t, 2, strings.Count(buf.String(), "msg=foo"))
+		require.NotContains(t, buf.String(), "repeated")
+	})
+
+	t.Run("window", func(t *testing.T) {
+		buf.Reset()
+
+		l.Info("foo")
+		mu.Lock()
+		now = now.Add(2 * time.Hour)
+		mu.Unlock()
+		l.Info("foo")
+		require.NoError(t, l.Close())
+		require.Equal(t, 2, strings.Count(buf.String(), "msg=foo"))
+		require.NotContains(t, buf.String(), "repeated")
+	})
+
+	t.Run("timer", func(t *testing.T) {
+		var buf syncBuffer
+		l := New(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithDeduplication(10*time.Millisecond),
+		)
+
+		for i := 0; i < 2; i++ {
+			l.Info("foo")
+		}
+		require.Eventually(t, func() bool {
+			return strings.Contains(buf.String(), "repeated=1")
+		}, time.Second, 5*time.Millisecond)
+	})
+}
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *syncBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync"
+)
+
+// deprecations holds the subjects passed to Deprecated that have already been
+// logged.
+var deprecations sync.Map
+
+// Deprecated logs a warning that subject is deprecated, suggesting replacement
+// if it is not empty. Each subject is logged at most once per process.
+//
+// The record's caller is the first caller outside the package that called
+// Deprecated, so a library calling
+//
+//	l.Deprecated("Client.Fetch", "use Client.Get")
+//
+// from Client.Fetch reports where the consumer called Client.Fetch rather than
+// where the library called Deprecated. The caller is included even if the
+// logger was created with WithCaller(false).
+func (l *L) Deprecated(subject, replacement string) {
+	if !l.enabled(context.Background(), slog.LevelWarn) {
+		return
+	}
+	if _, logged := deprecations.LoadOrStore(subject, struct{}{}); logged {
+		return
+	}
+
+	keyvals := []any{slog.String("deprecated", subject)}
+	if replacement != "" {
+		keyvals = append(keyvals, slog.String("replacement", replacement))
+	}
+	if pc := externalCallerPC(2); pc != 0 {
+		for _, a := range l.callerAttrs(pc) {
+			keyvals = append(keyvals, a)
+		}
+	}
+
+	c := l.clone()
+	c.showCaller = false
+	c.log(context.Background(), slog.LevelWarn, subject+" is deprecated", keyvals...)
+}
+
+// externalCallerPC returns the program counter of the first caller outside the
+// package of the caller at depth, as returned by callerPC, or that of the caller
+// at depth itself if every caller above it is in the same package.
+func externalCallerPC(depth int) uintptr {
+	var pcs [32]uintptr
+	n := runtime.Callers(depth+1, pcs[:])
+	if n == 0 {
+		return 0
+	}
+
+	pkg := funcPackage(callerFrame(pcs[0]).Function)
+	for _, pc := range pcs[1:n] {
+		if fn := callerFrame(pc).Function; fn != "" && funcPackage(fn) != pkg {
+			return pc
+		}
+	}
+	return pcs[0]
+}
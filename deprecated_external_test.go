@@ -0,0 +1,21 @@
+package logger_test
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/jasonhancock/go-logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecatedCaller(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.New(logger.WithDestination(&buf), logger.WithLevel("info"))
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.DeprecatedFetch(t, l)
+
+	require.Contains(t, buf.String(), "caller=github.com/jasonhancock/go-logger_test/deprecated_external_test.go:"+strconv.Itoa(line+1))
+}
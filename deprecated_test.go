@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerDeprecated(t *testing.T) {
+	t.Cleanup(resetDeprecations)
+
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false), WithKeyNames(KeyNames{Time: KeyOmit}))
+
+	subject := t.Name() + " flag --foo"
+	deprecatedFoo := func() { l.Deprecated(subject, "use --bar") }
+	deprecatedFoo()
+	deprecatedFoo()
+	l.New("sub").Deprecated(subject, "use --bar")
+	l.Deprecated(t.Name()+" Client.Fetch", "")
+
+	// Frames in this package, including the test itself, are internal, so the
+	// caller is the testing package that runs it. See TestDeprecatedCaller for a
+	// caller in another package.
+	require.Regexp(t, `^level=warn msg="TestLoggerDeprecated flag --foo is deprecated" src=go-logger.test deprecated="TestLoggerDeprecated flag --foo" replacement="use --bar" caller=testing/testing.go:\d+
+level=warn msg="TestLoggerDeprecated Client.Fetch is deprecated" src=go-logger.test deprecated="TestLoggerDeprecated Client.Fetch" caller=testing/testing.go:\d+
+$`, buf.String())
+}
+
+func TestExternalCallerPC(t *testing.T) {
+	require.Equal(t, "github.com/jasonhancock/go-logger", funcPackage("github.com/jasonhancock/go-logger.(*L).Info"))
+	require.Equal(t, "main", funcPackage("main.main"))
+	require.Equal(t, "testing", funcPackage(callerFrame(externalCallerPC(1)).Function))
+}
+
+// DeprecatedFetch is a deprecated API for TestDeprecatedCaller, which calls it
+// from another package.
+func DeprecatedFetch(t testing.TB, l *L) {
+	t.Cleanup(resetDeprecations)
+	l.Deprecated("DeprecatedFetch", "use Get")
+}
+
+// resetDeprecations forgets the subjects that have been logged so that tests
+// can be run more than once.
+func resetDeprecations() {
+	deprecations.Range(func(k, _ any) bool {
+		deprecations.Delete(k)
+		return true
+	})
+}
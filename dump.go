@@ -0,0 +1,35 @@
+package logger
+
+import "runtime"
+
+// goroutineDumpKey is the attribute holding a goroutine dump logged by
+// DumpGoroutines.
+const goroutineDumpKey = "goroutines"
+
+// DumpGoroutines writes the stacks of all goroutines. If the logger was created
+// with WithGoroutineDump and a writer, the dump is written there as is;
+// otherwise it is logged at the error level in a goroutines attribute.
+func (l *L) DumpGoroutines() {
+	if l == nil {
+		return
+	}
+
+	dump := goroutineStacks()
+	if l.dumpWriter != nil {
+		_, _ = l.dumpWriter.Write(dump)
+		return
+	}
+	l.Err("goroutine dump", goroutineDumpKey, string(dump))
+}
+
+// goroutineStacks returns the formatted stacks of all goroutines.
+func goroutineStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerDumpGoroutines(t *testing.T) {
+	t.Run("logged", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false))
+
+		l.DumpGoroutines()
+		require.Regexp(t, `level=err msg="goroutine dump" src=go-logger.test goroutines="goroutine \d+ \[running\]:.*TestLoggerDumpGoroutines`, buf.String())
+	})
+
+	t.Run("writer", func(t *testing.T) {
+		var buf, dump bytes.Buffer
+		l := New(WithDestination(&buf), WithLevel("info"), WithGoroutineDump(&dump))
+
+		l.DumpGoroutines()
+		require.Empty(t, buf.String())
+		require.Regexp(t, `^goroutine \d+ \[running\]:\n`, dump.String())
+		require.Contains(t, dump.String(), "TestLoggerDumpGoroutines")
+	})
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LevelOverride is a temporary level change made with TemporaryLevel.
+type LevelOverride struct {
+	once   sync.Once
+	timer  *time.Timer
+	level  slog.Level
+	revert func()
+}
+
+// Revert ends the override immediately. It is safe to call more than once and
+// after the override has expired.
+func (o *LevelOverride) Revert() {
+	o.once.Do(func() {
+		if o.timer == nil {
+			return
+		}
+		o.timer.Stop()
+		o.revert()
+	})
+}
+
+// levelOverrides tracks the active overrides of one level. The most recent one
+// is in effect, and restore puts back the original level once none remain.
+type levelOverrides struct {
+	active  []*LevelOverride
+	restore func()
+}
+
+// overrides maps the *slog.LevelVar or registryKey of each overridden level to
+// its levelOverrides.
+var (
+	overridesMu sync.Mutex
+	overrides   = map[any]*levelOverrides{}
+)
+
+// registryKey identifies a logger's level in a Registry.
+type registryKey struct {
+	registry *Registry
+	name     string
+}
+
+// TemporaryLevel changes the level to level for d, after which the previous
+// level is restored automatically. Both changes are logged. Overrides may
+// overlap: the most recent one remaining is in effect, and the level from before
+// the first is restored when the last one ends. If the logger uses a Registry,
+// only the logger and its descendants are affected; otherwise the change applies
+// to every logger sharing the root logger's configuration.
+func (l *L) TemporaryLevel(level string, d time.Duration) *LevelOverride {
+	if l == nil {
+		return &LevelOverride{}
+	}
+
+	o := &LevelOverride{level: ParseLevel(level).Level()}
+
+	var key any
+	var current func() slog.Level
+	var set func(slog.Level) (restore func())
+	if l.registry != nil {
+		name := strings.Join(l.src[1:], ".")
+		key = registryKey{l.registry, name}
+		current = func() slog.Level { return l.registry.Level(name) }
+		set = func(lvl slog.Level) func() {
+			prev := l.registry.swapLevel(name, &lvl)
+			return func() { l.registry.swapLevel(name, prev) }
+		}
+	} else {
+		key = l.level
+		current = l.level.Level
+		set = func(lvl slog.Level) func() {
+			prev := l.level.Level()
+			l.level.Set(lvl)
+			return func() { l.level.Set(prev) }
+		}
+	}
+
+	overridesMu.Lock()
+	prev := current()
+	restore := set(o.level)
+	s, ok := overrides[key]
+	if !ok {
+		s = &levelOverrides{restore: restore}
+		overrides[key] = s
+	}
+	s.active = append(s.active, o)
+	overridesMu.Unlock()
+
+	l.Warn("log level temporarily changed",
+		"new_level", levelLabel(o.level),
+		"previous_level", levelLabel(prev),
+		"duration", d,
+	)
+
+	o.revert = func() {
+		overridesMu.Lock()
+		s.active = slices.DeleteFunc(s.active, func(a *LevelOverride) bool { return a == o })
+		if len(s.active) == 0 {
+			s.restore()
+			delete(overrides, key)
+		} else {
+			set(s.active[len(s.active)-1].level)
+		}
+		lvl := current()
+		overridesMu.Unlock()
+
+		l.Warn("log level restored", "new_level", levelLabel(lvl))
+	}
+	o.timer = time.AfterFunc(d, o.Revert)
+	return o
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerTemporaryLevel(t *testing.T) {
+	buf := &syncBuffer{}
+	l := New(WithDestination(buf), WithLevel("info"), WithCaller(false))
+	sub := l.New("sub")
+
+	sub.Debug("before")
+	o := l.TemporaryLevel("debug", time.Hour)
+	sub.Debug("during")
+	o.Revert()
+	o.Revert()
+	sub.Debug("after")
+
+	out := buf.String()
+	require.NotContains(t, out, "msg=before")
+	require.Contains(t, out, "msg=during")
+	require.NotContains(t, out, "msg=after")
+	require.Contains(t, out, `level=warn msg="log level temporarily changed" src=go-logger.test new_level=debug previous_level=info duration=1h0m0s`)
+	require.Equal(t, 1, bytes.Count([]byte(out), []byte("log level restored")))
+
+	// Expiry.
+	l.TemporaryLevel("debug", 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return bytes.Contains([]byte(buf.String()), []byte(`msg="log level restored" src=go-logger.test new_level=info`))
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestLoggerTemporaryLevelRegistry(t *testing.T) {
+	var buf bytes.Buffer
+	reg := NewRegistry()
+	l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false), WithRegistry(reg))
+	db := l.New("db")
+	http := l.New("http")
+
+	reg.SetLevel("db", "warn")
+	o := db.TemporaryLevel("debug", time.Hour)
+	db.Debug("db during")
+	http.Debug("http during")
+	require.Equal(t, []LoggerInfo{
+		{Name: "", Level: "info", Explicit: true},
+		{Name: "db", Level: "debug", Explicit: true},
+		{Name: "http", Level: "info"},
+	}, reg.Loggers())
+
+	o.Revert()
+	require.Equal(t, "warn", reg.Loggers()[1].Level)
+
+	require.Contains(t, buf.String(), "msg=\"db during\"")
+	require.NotContains(t, buf.String(), "http during")
+}
+
+func TestLoggerTemporaryLevelOverlapping(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"level", nil},
+		{"registry", []Option{WithRegistry(NewRegistry())}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &syncBuffer{}
+			l := New(append([]Option{WithDestination(buf), WithLevel("info"), WithCaller(false)}, tt.opts...)...).New("sub")
+
+			first := l.TemporaryLevel("debug", time.Hour)
+			second := l.TemporaryLevel("warn", time.Hour)
+			l.Info("second")
+			first.Revert()
+			l.Info("still second")
+			second.Revert()
+			l.Info("restored")
+
+			l.TemporaryLevel("debug", 10*time.Millisecond)
+			l.TemporaryLevel("debug", 20*time.Millisecond)
+			require.Eventually(t, func() bool {
+				return bytes.Count([]byte(buf.String()), []byte("new_level=info")) == 2
+			}, time.Second, 5*time.Millisecond)
+			l.Debug("expired")
+
+			out := buf.String()
+			require.NotContains(t, out, "msg=second")
+			require.NotContains(t, out, "still second")
+			require.Contains(t, out, "msg=restored")
+			require.NotContains(t, out, "msg=expired")
+			require.Regexp(t, `msg="log level restored" .*new_level=warn\n`, out)
+		})
+	}
+}
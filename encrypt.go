@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Encrypted output is a sequence of independent frames, one per write. Each frame
+// is a 4 byte big-endian length followed by that many bytes holding the nonce and
+// the AES-GCM sealed data. A partially written file can be decrypted up to its
+// last complete frame.
+const (
+	frameLenSize = 4
+	maxFrameSize = 16 << 20
+)
+
+// newEncryptWriter returns a writer encrypting its output to w with AES-GCM. The
+// key must be 16, 24, or 32 bytes long to select AES-128, AES-192, or AES-256.
+func newEncryptWriter(key []byte, w io.Writer) (*encryptWriter, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptWriter{w: w, aead: aead}, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("logger: invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptWriter seals each write into its own frame. The main and audit handlers
+// can share a destination, so writes are serialized here.
+type encryptWriter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	aead cipher.AEAD
+	err  error
+}
+
+func (w *encryptWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	ns := w.aead.NonceSize()
+	frame := make([]byte, frameLenSize+ns, frameLenSize+ns+len(p)+w.aead.Overhead())
+	if _, err := rand.Read(frame[frameLenSize:]); err != nil {
+		return 0, err
+	}
+	frame = w.aead.Seal(frame, frame[frameLenSize:], p, nil)
+	binary.BigEndian.PutUint32(frame, uint32(len(frame)-frameLenSize))
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.w.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// DecryptStream decrypts output written with WithEncryption from src to dst. If
+// src ends partway through a frame, everything before it is still written to dst
+// and io.ErrUnexpectedEOF is returned.
+func DecryptStream(dst io.Writer, src io.Reader, key []byte) error {
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	ns := aead.NonceSize()
+
+	r := bufio.NewReader(src)
+	var hdr [frameLenSize]byte
+	var buf []byte
+	for frame := 1; ; frame++ {
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		n := binary.BigEndian.Uint32(hdr[:])
+		if n < uint32(ns+aead.Overhead()) || n > maxFrameSize {
+			return fmt.Errorf("frame %d: invalid length %d", frame, n)
+		}
+
+		if cap(buf) < int(n) {
+			buf = make([]byte, n)
+		}
+		buf = buf[:n]
+		if _, err := io.ReadFull(r, buf); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		plain, err := aead.Open(buf[ns:ns], buf[:ns], buf[ns:], nil)
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", frame, err)
+		}
+		if _, err := dst.Write(plain); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerEncryption(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithEncryption(key),
+		WithCaller(false),
+	)
+
+	l.Info("foo", "key1", "value1")
+	l.Info("bar")
+	require.NotContains(t, buf.String(), "foo")
+
+	var out bytes.Buffer
+	require.NoError(t, DecryptStream(&out, bytes.NewReader(buf.Bytes()), key))
+	require.Contains(t, out.String(), "msg=foo src=go-logger.test key1=value1\n")
+	require.Contains(t, out.String(), "msg=bar src=go-logger.test\n")
+
+	t.Run("truncated", func(t *testing.T) {
+		var out bytes.Buffer
+		err := DecryptStream(&out, bytes.NewReader(buf.Bytes()[:buf.Len()-5]), key)
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		require.Contains(t, out.String(), "msg=foo")
+		require.NotContains(t, out.String(), "msg=bar")
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		err := DecryptStream(io.Discard, bytes.NewReader(buf.Bytes()), bytes.Repeat([]byte{2}, 32))
+		require.ErrorContains(t, err, "frame 1:")
+	})
+}
+
+func TestLoggerEncryptionCompressed(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 16)
+
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithEncryption(key),
+		WithCompression(CompressionGzip),
+		WithCaller(false),
+	)
+
+	l.Info("foo")
+	require.NoError(t, l.Close())
+
+	var compressed bytes.Buffer
+	require.NoError(t, DecryptStream(&compressed, &buf, key))
+	zr, err := gzip.NewReader(&compressed)
+	require.NoError(t, err)
+	data, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "msg=foo")
+}
+
+func TestLoggerEncryptionInvalidKey(t *testing.T) {
+	var buf bytes.Buffer
+	var reported []error
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithEncryption([]byte("short")),
+		WithErrorHandler(func(err error) { reported = append(reported, err) }),
+	)
+
+	l.Info("foo")
+	require.Empty(t, buf.String())
+	require.Len(t, reported, 2)
+	require.ErrorContains(t, reported[0], "invalid encryption key")
+}
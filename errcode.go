@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// errorCodeKey is the attribute holding the stable code of an error record.
+const errorCodeKey = "code"
+
+// ErrorCoder is implemented by errors carrying a stable code, which is logged as
+// the code attribute when error codes are enforced with WithErrorCodes.
+type ErrorCoder interface {
+	ErrorCode() string
+}
+
+var errorCodes sync.Map
+
+// RegisterErrorCode declares a known error code along with a description of
+// what it means. Once any code has been registered, loggers enforcing error
+// codes flag records using codes that haven't been.
+func RegisterErrorCode(code, description string) {
+	errorCodes.Store(code, description)
+}
+
+// ErrorCodes returns the registered error codes and their descriptions.
+func ErrorCodes() map[string]string {
+	codes := make(map[string]string)
+	errorCodes.Range(func(k, v any) bool {
+		codes[k.(string)] = v.(string)
+		return true
+	})
+	return codes
+}
+
+func errorCodesRegistered() bool {
+	registered := false
+	errorCodes.Range(func(any, any) bool {
+		registered = true
+		return false
+	})
+	return registered
+}
+
+// errorCode returns the code of the first error in err's chain implementing
+// ErrorCoder.
+func errorCode(err error) string {
+	var c ErrorCoder
+	if errors.As(err, &c) {
+		return c.ErrorCode()
+	}
+	return ""
+}
+
+// checkErrorCode ensures an error record has a code attribute, deriving it from
+// an error attribute implementing ErrorCoder if needed. It returns the
+// attributes to add to the record.
+func (l *L) checkErrorCode(r slog.Record) []slog.Attr {
+	var code string
+	var derived bool
+	find := func(a slog.Attr) bool {
+		if a.Key == errorCodeKey {
+			code, derived = a.Value.String(), false
+			return true
+		}
+		if err, ok := a.Value.Any().(error); ok && code == "" {
+			code, derived = errorCode(err), true
+		}
+		return true
+	}
+	for _, a := range l.attrs {
+		find(a)
+	}
+	r.Attrs(find)
+
+	if code == "" {
+		return []slog.Attr{slog.String(loggingErrorKey, "missing error code")}
+	}
+
+	var attrs []slog.Attr
+	if derived {
+		attrs = append(attrs, slog.String(errorCodeKey, code))
+	}
+	if _, ok := errorCodes.Load(code); !ok && errorCodesRegistered() {
+		attrs = append(attrs, slog.String(loggingErrorKey, fmt.Sprintf("unregistered error code %q", code)))
+	}
+	return attrs
+}
+
+// hasKey reports whether keyvals contain an attribute named key.
+func hasKey(keyvals []any, key string) bool {
+	for i := 0; i < len(keyvals); i++ {
+		switch k := keyvals[i].(type) {
+		case slog.Attr:
+			if k.Key == key {
+				return true
+			}
+		case string:
+			if k == key {
+				return true
+			}
+			i++
+		}
+	}
+	return false
+}
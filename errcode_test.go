@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type codedError struct {
+	code string
+}
+
+func (e codedError) Error() string     { return "coded failure" }
+func (e codedError) ErrorCode() string { return e.code }
+
+func TestLoggerErrorCodes(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false), WithKeyNames(KeyNames{Time: KeyOmit}), WithErrorCodes())
+
+	t.Run("unregistered codes allowed", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.Info("no code needed")
+		l.Err("explicit", "code", "E1")
+		l.Err("derived", "error", fmt.Errorf("wrapped: %w", codedError{"E2"}))
+		l.LogError("log error", codedError{"E3"})
+		l.LogError("log error explicit", codedError{"E3"}, "code", "E4")
+		l.With("code", "E5").Err("logger attr")
+		l.Err("missing")
+
+		require.Equal(t, `level=info msg="no code needed" src=go-logger.test
+level=err msg=explicit src=go-logger.test code=E1
+level=err msg=derived src=go-logger.test error="wrapped: coded failure" code=E2
+level=err msg="log error" src=go-logger.test code=E3 error="coded failure"
+level=err msg="log error explicit" src=go-logger.test code=E4 error="coded failure"
+level=err msg="logger attr" src=go-logger.test code=E5
+level=err msg=missing src=go-logger.test logging_error="missing error code"
+`, buf.String())
+	})
+
+	t.Run("registered", func(t *testing.T) {
+		defer buf.Reset()
+		defer resetErrorCodes()
+
+		RegisterErrorCode("E1", "something broke")
+		require.Equal(t, map[string]string{"E1": "something broke"}, ErrorCodes())
+
+		l.Err("known", "code", "E1")
+		l.Err("unknown", "code", "E2")
+		require.Equal(t, `level=err msg=known src=go-logger.test code=E1
+level=err msg=unknown src=go-logger.test code=E2 logging_error="unregistered error code \"E2\""
+`, buf.String())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false))
+		l.LogError("foo", errors.New("bar"))
+		require.NotContains(t, buf.String(), loggingErrorKey)
+	})
+}
+
+func resetErrorCodes() {
+	errorCodes = sync.Map{}
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// Attributes added by LogError for loggers created with WithErrorVerbose.
+const (
+	errorVerboseKey = "error_verbose"
+	errorChainKey   = "error_chain"
+)
+
+// errorDetails returns the %+v rendering of err, if it differs from its
+// message, and the type and message of each error in its chain.
+func errorDetails(err error) []any {
+	var attrs []any
+	if v := fmt.Sprintf("%+v", err); v != err.Error() {
+		attrs = append(attrs, slog.String(errorVerboseKey, v))
+	}
+	return append(attrs, slog.Any(errorChainKey, errorChain(err, nil)))
+}
+
+// errorChain appends the type and message of err and each error it wraps to
+// chain, depth first.
+func errorChain(err error, chain []string) []string {
+	chain = append(chain, fmt.Sprintf("%T: %s", err, err))
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if e != nil {
+				chain = errorChain(e, chain)
+			}
+		}
+	default:
+		if e := errors.Unwrap(err); e != nil {
+			chain = errorChain(e, chain)
+		}
+	}
+	return chain
+}
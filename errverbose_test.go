@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type stackError struct {
+	msg string
+}
+
+func (e stackError) Error() string { return e.msg }
+
+func (e stackError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprintf(s, "%s\nmain.run\n\tmain.go:10", e.msg)
+		return
+	}
+	fmt.Fprint(s, e.msg)
+}
+
+func TestLoggerErrorVerbose(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false), WithKeyNames(KeyNames{Time: KeyOmit}), WithErrorVerbose())
+
+	t.Run("chain", func(t *testing.T) {
+		defer buf.Reset()
+
+		err := fmt.Errorf("loading config: %w", &fs.PathError{Op: "open", Path: "/etc/app", Err: fs.ErrNotExist})
+		l.LogError("failed", err)
+		require.Equal(t, `level=err msg=failed src=go-logger.test error_chain="[*fmt.wrapError: loading config: open /etc/app: file does not exist *fs.PathError: open /etc/app: file does not exist *errors.errorString: file does not exist]" error="loading config: open /etc/app: file does not exist"
+`, buf.String())
+	})
+
+	t.Run("joined", func(t *testing.T) {
+		require.Equal(t,
+			[]string{"*errors.joinError: a\nb", "*errors.errorString: a", "*errors.errorString: b"},
+			errorChain(errors.Join(errors.New("a"), errors.New("b")), nil),
+		)
+	})
+
+	t.Run("stack", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.LogError("failed", stackError{"boom"})
+		require.Equal(t, `level=err msg=failed src=go-logger.test error_verbose="boom\nmain.run\n\tmain.go:10" error_chain="[logger.stackError: boom]" error=boom
+`, buf.String())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false))
+		l.LogError("failed", stackError{"boom"})
+		require.NotContains(t, buf.String(), errorChainKey)
+		require.NotContains(t, buf.String(), errorVerboseKey)
+	})
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// eventKey is the attribute holding the name of events logged with Event.
+const eventKey = "event"
+
+// Event is a canonical, typed log record such as a user login or a failed
+// payment. Defining events as types gives a central place to declare them and
+// compile-time checking of their fields:
+//
+//	type UserLogin struct {
+//		UserID string
+//		Method string
+//	}
+//
+//	func (e UserLogin) EventName() string { return "user_login" }
+//
+//	func (e UserLogin) EventAttrs() []slog.Attr {
+//		return []slog.Attr{slog.String("user_id", e.UserID), slog.String("method", e.Method)}
+//	}
+type Event interface {
+	EventName() string
+	EventAttrs() []slog.Attr
+}
+
+// LeveledEvent is an Event logged at a level other than info.
+type LeveledEvent interface {
+	Event
+	EventLevel() slog.Level
+}
+
+// Event logs ev with its name as both the message and the event attribute,
+// followed by its attributes. Events are logged at the info level unless they
+// implement LeveledEvent.
+func (l *L) Event(ev Event) {
+	lvl := eventLevel(ev)
+	if !l.enabled(context.Background(), lvl) {
+		return
+	}
+	l.log(context.Background(), lvl, ev.EventName(), eventKeyvals(ev)...)
+}
+
+// EventCtx logs ev like Event, with a context.
+func (l *L) EventCtx(ctx context.Context, ev Event) {
+	lvl := eventLevel(ev)
+	if !l.traceForced(ctx) && !l.enabled(ctx, lvl) {
+		return
+	}
+	l.log(ctx, lvl, ev.EventName(), eventKeyvals(ev)...)
+}
+
+func eventLevel(ev Event) slog.Level {
+	if le, ok := ev.(LeveledEvent); ok {
+		return le.EventLevel()
+	}
+	return slog.LevelInfo
+}
+
+func eventKeyvals(ev Event) []any {
+	attrs := ev.EventAttrs()
+	keyvals := make([]any, 0, len(attrs)+1)
+	keyvals = append(keyvals, slog.String(eventKey, ev.EventName()))
+	for _, a := range attrs {
+		keyvals = append(keyvals, a)
+	}
+	return keyvals
+}
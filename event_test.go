@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type userLogin struct {
+	UserID string
+}
+
+func (e userLogin) EventName() string { return "user_login" }
+
+func (e userLogin) EventAttrs() []slog.Attr {
+	return []slog.Attr{slog.String("user_id", e.UserID)}
+}
+
+type paymentFailed struct {
+	Amount int
+}
+
+func (e paymentFailed) EventName() string { return "payment_failed" }
+
+func (e paymentFailed) EventAttrs() []slog.Attr {
+	return []slog.Attr{slog.Int("amount", e.Amount)}
+}
+
+func (e paymentFailed) EventLevel() slog.Level { return slog.LevelWarn }
+
+func TestLoggerEvent(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("info"))
+
+	l.Event(userLogin{UserID: "42"})
+	_, _, line, _ := runtime.Caller(0)
+	l.EventCtx(context.Background(), paymentFailed{Amount: 100})
+
+	out := buf.String()
+	require.Contains(t, out, "level=info msg=user_login src=go-logger.test event=user_login user_id=42 caller=")
+	require.Contains(t, out, "level=warn msg=payment_failed src=go-logger.test event=payment_failed amount=100 caller=")
+	require.Contains(t, out, fmt.Sprintf("caller=github.com/jasonhancock/go-logger/event_test.go:%d\n", line-1))
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// stubExit replaces osExit for the duration of the test, recording the exit
+// code.
+func stubExit(t *testing.T) *int {
+	t.Helper()
+	code := -1
+	osExit = func(c int) { code = c }
+	t.Cleanup(func() { osExit = os.Exit })
+	return &code
+}
+
+func TestLoggerFatalIfError(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false), WithKeyNames(KeyNames{Time: KeyOmit}))
+
+	t.Run("nil", func(t *testing.T) {
+		defer buf.Reset()
+		code := stubExit(t)
+
+		l.FatalIfError(nil, "connecting to database")
+		require.Equal(t, -1, *code)
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		defer buf.Reset()
+		code := stubExit(t)
+
+		l.FatalIfError(errors.New("connection refused"), "connecting to database", "host", "db1")
+		require.Equal(t, 1, *code)
+		require.Equal(t, `level=fatal msg="connecting to database" src=go-logger.test host=db1 error="connection refused"
+`, buf.String())
+	})
+
+	t.Run("nil logger", func(t *testing.T) {
+		code := stubExit(t)
+
+		var l *L
+		l.FatalIfError(errors.New("connection refused"), "connecting to database")
+		require.Equal(t, 1, *code)
+	})
+}
+
+func TestMust(t *testing.T) {
+	code := stubExit(t)
+	require.Equal(t, 42, Must(42, nil))
+	require.Equal(t, -1, *code)
+
+	// Must logs to the Default logger, which writes to stdout.
+	f, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	require.NoError(t, err)
+	defer f.Close()
+	stdout := os.Stdout
+	os.Stdout = f
+	defer func() { os.Stdout = stdout }()
+
+	require.Equal(t, 0, Must(0, errors.New("bad config")))
+	_, _, line, _ := runtime.Caller(0)
+	require.Equal(t, 1, *code)
+
+	b, err := os.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.Contains(t, string(b), fmt.Sprintf(`level=fatal msg="unrecoverable error" src=default error="bad config" caller=github.com/jasonhancock/go-logger/fatal_test.go:%d
+`, line-1))
+}
@@ -0,0 +1,293 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileConfig configures a File.
+type FileConfig struct {
+	// Path is the file records are written to. Rotated files are renamed to
+	// Path followed by a dot and the UTC time of the rotation.
+	Path string
+
+	// MaxSize is the size in bytes at which the file is rotated. A record is
+	// never split across files. Defaults to 100MB.
+	MaxSize int64
+
+	// MaxBackups is the number of rotated files kept. Older ones are removed.
+	// Zero keeps them all.
+	MaxBackups int
+
+	// Header, if set, is called each time the file is opened, including after
+	// a rotation, and its output is written at the start of the file, or at the
+	// end of an existing one, so that every file describes how it was produced.
+	// See FileHeader.
+	Header func() []byte
+
+	// Footer, if set, is called before the file is rotated or closed, and its
+	// output is written at the end of the file.
+	Footer func() []byte
+
+	// Compression, if set to CompressionGzip, compresses rotated files, adding
+	// .gz to their names.
+	Compression string
+
+	// EncryptionKey, if set, encrypts rotated files, after compressing them, as
+	// WithEncryption does, adding .enc to their names. Decrypt them with
+	// DecryptStream.
+	EncryptionKey []byte
+
+	// Checksums, if set, appends the SHA-256 checksum of each rotated file,
+	// after compression and encryption, to a manifest at Path followed by
+	// .sha256, in the format read by sha256sum -c.
+	Checksums bool
+
+	// OnError is called when archiving, or removing, rotated files in the
+	// background fails. A rotated file that fails to be archived is left as it
+	// was.
+	OnError func(error)
+}
+
+// rotationLayout formats the time of a rotation in the names of rotated files.
+// Names sort in the order the files were rotated.
+const rotationLayout = "20060102T150405.000000000Z"
+
+// File is a destination writing to a file that is rotated once it reaches a
+// maximum size.
+type File struct {
+	cfg FileConfig
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+	last time.Time
+
+	// Rotated files are archived, if needed, in the background, in order.
+	archiveQ chan string
+	wg       sync.WaitGroup
+}
+
+// NewFile opens, or creates, the file at cfg.Path for appending.
+func NewFile(cfg FileConfig) (*File, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("file path is required")
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = 100 << 20
+	}
+	if cfg.Compression != "" && !strings.EqualFold(cfg.Compression, CompressionGzip) {
+		return nil, fmt.Errorf("logger: unsupported compression %q", cfg.Compression)
+	}
+	if cfg.EncryptionKey != nil {
+		if _, err := newGCM(cfg.EncryptionKey); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o700); err != nil {
+		return nil, err
+	}
+
+	f := &File{cfg: cfg}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	if f.archiving() {
+		f.archiveQ = make(chan string, 16)
+		f.wg.Add(1)
+		go f.archiveRotated()
+	}
+	return f, nil
+}
+
+// Write appends p to the file, rotating it first if p would take it past
+// MaxSize.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.f == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.size > 0 && f.size+int64(len(p)) > f.cfg.MaxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.f.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the file regardless of its size, for example in response to a
+// signal from an external log rotation tool.
+func (f *File) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.f == nil {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+// Close closes the file, waiting for rotated files to be archived.
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.f == nil {
+		return nil
+	}
+	err := errors.Join(f.writeFooter(), f.f.Close())
+	f.f = nil
+	if f.archiveQ != nil {
+		close(f.archiveQ)
+		f.wg.Wait()
+	}
+	return err
+}
+
+// rotate renames the current file and opens a new one. f.mu must be held.
+func (f *File) rotate() error {
+	if err := errors.Join(f.writeFooter(), f.f.Close()); err != nil {
+		return err
+	}
+	f.f = nil
+
+	// Keep rotation times unique so that rotations within the clock's
+	// resolution don't overwrite each other.
+	now := time.Now().UTC()
+	if !now.After(f.last) {
+		now = f.last.Add(time.Nanosecond)
+	}
+	f.last = now
+
+	// Reopen the file even if it couldn't be renamed, so that writes carry on.
+	rotated := f.cfg.Path + "." + now.Format(rotationLayout)
+	renameErr := os.Rename(f.cfg.Path, rotated)
+	if err := f.open(); err != nil {
+		return errors.Join(renameErr, err)
+	}
+	if renameErr != nil {
+		return renameErr
+	}
+
+	if f.archiveQ != nil {
+		f.archiveQ <- rotated
+		return nil
+	}
+	return f.prune(rotated)
+}
+
+// open opens the file and writes the header. f.mu must be held, or f not yet
+// shared.
+func (f *File) open() error {
+	file, err := os.OpenFile(f.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.f = file
+	f.size = fi.Size()
+
+	if f.cfg.Header != nil {
+		n, err := file.Write(f.cfg.Header())
+		f.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFooter writes the footer, if any. f.mu must be held.
+func (f *File) writeFooter() error {
+	if f.cfg.Footer == nil {
+		return nil
+	}
+	_, err := f.f.Write(f.cfg.Footer())
+	return err
+}
+
+// backups returns the paths of the rotated files, oldest first.
+func (f *File) backups() ([]string, error) {
+	matches, err := filepath.Glob(f.cfg.Path + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, m := range matches {
+		suffix := m[len(f.cfg.Path)+1:]
+		if len(suffix) < len(rotationLayout) || strings.HasSuffix(suffix, archiveTmp) {
+			continue
+		}
+		if _, err := time.Parse(rotationLayout, suffix[:len(rotationLayout)]); err == nil {
+			paths = append(paths, m)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// prune removes the oldest rotated files beyond MaxBackups, counting only those
+// rotated no later than the file at upTo, so that files waiting to be archived
+// are left alone.
+func (f *File) prune(upTo string) error {
+	if f.cfg.MaxBackups <= 0 {
+		return nil
+	}
+
+	paths, err := f.backups()
+	if err != nil {
+		return err
+	}
+	n := len(f.cfg.Path) + 1 + len(rotationLayout)
+	for len(paths) > 0 && paths[len(paths)-1][:n] > upTo[:n] {
+		paths = paths[:len(paths)-1]
+	}
+
+	var errs []error
+	for len(paths) > f.cfg.MaxBackups {
+		errs = append(errs, os.Remove(paths[0]))
+		paths = paths[1:]
+	}
+	return errors.Join(errs...)
+}
+
+// FileHeader returns a function for FileConfig.Header rendering a "log file
+// opened" record in the given format, holding the attributes added by
+// WithBuildInfo and WithHostInfo, the start_time at which FileHeader was called,
+// which is usually close to the start of the process, and keyvals, such as a
+// hash of the configuration.
+func FileHeader(format string, keyvals ...any) func() []byte {
+	start := time.Now()
+	return func() []byte {
+		var buf bytes.Buffer
+		l := New(
+			WithDestination(&buf),
+			WithFormat(format),
+			WithLevel("info"),
+			WithCaller(false),
+			WithBuildInfo(),
+			WithHostInfo(),
+		)
+		l.Info("log file opened", append([]any{"start_time", start}, keyvals...)...)
+		return buf.Bytes()
+	}
+}
@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	f, err := NewFile(FileConfig{
+		Path:       path,
+		MaxSize:    20,
+		MaxBackups: 2,
+		Header:     func() []byte { return []byte("# header\n") },
+		Footer:     func() []byte { return []byte("# footer\n") },
+	})
+	require.NoError(t, err)
+
+	write := func(s string) {
+		t.Helper()
+		n, err := f.Write([]byte(s))
+		require.NoError(t, err)
+		require.Equal(t, len(s), n)
+	}
+
+	write("record 1\n")
+	write("record 2\n") // rotates before writing
+	require.NoError(t, f.Rotate())
+	write("record 3\n")
+	write("record 4\n")
+	require.NoError(t, f.Close())
+	require.NoError(t, f.Close())
+
+	_, err = f.Write([]byte("closed\n"))
+	require.ErrorIs(t, err, os.ErrClosed)
+
+	backups, err := f.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2, "the oldest backup is pruned")
+
+	var contents []string
+	for _, p := range append(backups, path) {
+		b, err := os.ReadFile(p)
+		require.NoError(t, err)
+		contents = append(contents, string(b))
+	}
+	require.Equal(t, []string{
+		"# header\nrecord 2\n# footer\n",
+		"# header\nrecord 3\n# footer\n",
+		"# header\nrecord 4\n# footer\n",
+	}, contents)
+
+	// Reopening appends to the existing file.
+	f, err = NewFile(FileConfig{Path: path, Header: func() []byte { return []byte("# reopened\n") }})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(string(b), "# footer\n# reopened\n"))
+
+	_, err = NewFile(FileConfig{})
+	require.Error(t, err)
+}
+
+func TestFileHeader(t *testing.T) {
+	header := FileHeader(FormatLogFmt, "config_hash", "abc123")
+	line := string(header())
+	require.Regexp(t, `^ts=\S+ level=info msg="log file opened" go_version=go\S+ .*hostname=\S+ pid=\d+ src=\S+ start_time=\S+ config_hash=abc123\n$`, line)
+}
+
+func TestFileArchive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	key := make([]byte, 32)
+
+	var errs []error
+	f, err := NewFile(FileConfig{
+		Path:          path,
+		MaxSize:       1 << 20,
+		Compression:   CompressionGzip,
+		EncryptionKey: key,
+		Checksums:     true,
+		OnError:       func(err error) { errs = append(errs, err) },
+	})
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("record 1\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Rotate())
+	_, err = f.Write([]byte("record 2\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Rotate())
+	require.NoError(t, f.Close())
+	require.Empty(t, errs)
+
+	backups, err := f.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+
+	manifest, err := os.ReadFile(path + ".sha256")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(manifest), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	for i, p := range backups {
+		require.True(t, strings.HasSuffix(p, ".gz.enc"), p)
+
+		sum, err := fileChecksum(p)
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(sum)+"  "+filepath.Base(p), lines[i])
+
+		enc, err := os.Open(p)
+		require.NoError(t, err)
+		var compressed bytes.Buffer
+		require.NoError(t, DecryptStream(&compressed, enc, key))
+		enc.Close()
+
+		zr, err := gzip.NewReader(&compressed)
+		require.NoError(t, err)
+		b, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("record %d\n", i+1), string(b))
+	}
+}
+
+func TestFileChecksumsOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := NewFile(FileConfig{Path: path, Checksums: true, MaxBackups: 1})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = f.Write([]byte("record\n"))
+		require.NoError(t, err)
+		require.NoError(t, f.Rotate())
+	}
+	require.NoError(t, f.Close())
+
+	backups, err := f.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	b, err := os.ReadFile(backups[0])
+	require.NoError(t, err)
+	require.Equal(t, "record\n", string(b))
+
+	// Entries for pruned files remain in the manifest.
+	manifest, err := os.ReadFile(path + ".sha256")
+	require.NoError(t, err)
+	sum := sha256.Sum256([]byte("record\n"))
+	require.Equal(t, 3, strings.Count(string(manifest), hex.EncodeToString(sum[:])))
+	require.Contains(t, string(manifest), filepath.Base(backups[0]))
+
+	_, err = NewFile(FileConfig{Path: path, Compression: "zstd"})
+	require.EqualError(t, err, `logger: unsupported compression "zstd"`)
+	_, err = NewFile(FileConfig{Path: path, EncryptionKey: []byte("short")})
+	require.ErrorContains(t, err, "logger: invalid encryption key")
+}
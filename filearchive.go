@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// archiveTmp is the suffix of rotated files being archived.
+const archiveTmp = ".tmp"
+
+// archiving reports whether rotated files are processed before being kept.
+func (f *File) archiving() bool {
+	return f.cfg.Compression != "" || f.cfg.EncryptionKey != nil || f.cfg.Checksums
+}
+
+// archiveRotated archives the rotated files sent to f.archiveQ until it is
+// closed.
+func (f *File) archiveRotated() {
+	defer f.wg.Done()
+	for path := range f.archiveQ {
+		if err := f.archive(path); err != nil {
+			f.report(fmt.Errorf("archiving %s: %w", path, err))
+			continue
+		}
+		f.report(f.prune(path))
+	}
+}
+
+func (f *File) report(err error) {
+	if err != nil && f.cfg.OnError != nil {
+		f.cfg.OnError(err)
+	}
+}
+
+// archive compresses and encrypts the rotated file at path as configured,
+// replacing it with the result, and records its checksum in the manifest.
+func (f *File) archive(path string) error {
+	name := path
+	if f.cfg.Compression != "" {
+		name += ".gz"
+	}
+	if f.cfg.EncryptionKey != nil {
+		name += ".enc"
+	}
+
+	var sum []byte
+	var err error
+	if name == path {
+		sum, err = fileChecksum(path)
+	} else {
+		sum, err = f.transform(path, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !f.cfg.Checksums {
+		return nil
+	}
+	return appendManifest(f.cfg.Path+".sha256", name, sum)
+}
+
+// transform writes the compressed and encrypted contents of src to dst,
+// removes src, and returns the checksum of dst.
+func (f *File) transform(src, dst string) (sum []byte, err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	tmp := dst + archiveTmp
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	h := sha256.New()
+	var w io.Writer = io.MultiWriter(out, h)
+
+	// Buffer the input to the encrypter, which seals each write into its own
+	// frame, so that frames aren't as small as the compressor's writes.
+	var bw *bufio.Writer
+	if f.cfg.EncryptionKey != nil {
+		ew, err := newEncryptWriter(f.cfg.EncryptionKey, w)
+		if err != nil {
+			return nil, err
+		}
+		bw = bufio.NewWriterSize(ew, 64<<10)
+		w = bw
+	}
+
+	var zw *gzip.Writer
+	if f.cfg.Compression != "" {
+		zw = gzip.NewWriter(w)
+		w = zw
+	}
+
+	if _, err := io.Copy(w, in); err != nil {
+		return nil, err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if bw != nil {
+		if err := bw.Flush(); err != nil {
+			return nil, err
+		}
+	}
+	if err := out.Sync(); err != nil {
+		return nil, err
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), os.Remove(src)
+}
+
+// fileChecksum returns the SHA-256 checksum of the file at path.
+func fileChecksum(path string) ([]byte, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, in); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// appendManifest appends a line for the file at path with checksum sum to the
+// manifest in the format of sha256sum. Paths are relative to the manifest.
+func appendManifest(manifest, path string, sum []byte) error {
+	m, err := os.OpenFile(manifest, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(m, "%s  %s\n", hex.EncodeToString(sum), filepath.Base(path))
+	return errors.Join(err, m.Close())
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Filter decides whether a record is written. The record passed to a filter
+// holds the attributes added to the logger with With and New followed by those
+// passed at the call site, before caller and other logger generated attributes
+// are added.
+type Filter func(ctx context.Context, r slog.Record) bool
+
+// LevelAtLeast matches records at or above lvl.
+func LevelAtLeast(lvl slog.Level) Filter {
+	return func(_ context.Context, r slog.Record) bool {
+		return r.Level >= lvl
+	}
+}
+
+// HasAttr matches records with an attribute named key.
+func HasAttr(key string) Filter {
+	return func(_ context.Context, r slog.Record) bool {
+		_, ok := recordAttr(r, key)
+		return ok
+	}
+}
+
+// AttrEquals matches records with an attribute named key whose value has the
+// same string form as value, so AttrEquals("user_id", "42") matches both
+// user_id=42 and user_id="42". If the attribute occurs more than once, the last
+// occurrence is used.
+func AttrEquals(key string, value any) Filter {
+	want := fmt.Sprint(value)
+	return func(_ context.Context, r slog.Record) bool {
+		v, ok := recordAttr(r, key)
+		return ok && v.String() == want
+	}
+}
+
+// MessageContains matches records whose message contains substr.
+func MessageContains(substr string) Filter {
+	return func(_ context.Context, r slog.Record) bool {
+		return strings.Contains(r.Message, substr)
+	}
+}
+
+// And matches records matched by all of filters.
+func And(filters ...Filter) Filter {
+	return func(ctx context.Context, r slog.Record) bool {
+		for _, f := range filters {
+			if !f(ctx, r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches records matched by any of filters.
+func Or(filters ...Filter) Filter {
+	return func(ctx context.Context, r slog.Record) bool {
+		for _, f := range filters {
+			if f(ctx, r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches records not matched by f.
+func Not(f Filter) Filter {
+	return func(ctx context.Context, r slog.Record) bool {
+		return !f(ctx, r)
+	}
+}
+
+// recordAttr returns the resolved value of the last top level attribute named
+// key in r.
+func recordAttr(r slog.Record, key string) (slog.Value, bool) {
+	var v slog.Value
+	var found bool
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == key {
+			v, found = a.Value.Resolve(), true
+		}
+		return true
+	})
+	return v, found
+}
+
+// filtered reports whether any of the logger's filters reject r.
+func (l *L) filtered(ctx context.Context, r slog.Record) bool {
+	fr := l.fullRecord(r)
+	for _, f := range l.filters {
+		if !f(ctx, fr) {
+			return true
+		}
+	}
+	return false
+}
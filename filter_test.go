@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerFilter(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("debug"),
+		WithCaller(false),
+		WithFilter(
+			Or(LevelAtLeast(slog.LevelWarn), AttrEquals("user_id", 42)),
+			Not(MessageContains("healthz")),
+		),
+	)
+
+	l.Debug("dropped", "user_id", 7)
+	l.Debug("kept call site", "user_id", 42)
+	l.With("user_id", "42").Info("kept logger attr")
+	l.With("user_id", 42).Info("dropped override", "user_id", 7)
+	l.Warn("kept warn")
+	l.Warn("GET /healthz")
+
+	out := buf.String()
+	require.NotContains(t, out, "msg=dropped")
+	require.Contains(t, out, `msg="kept call site"`)
+	require.Contains(t, out, `msg="kept logger attr"`)
+	require.Contains(t, out, `msg="kept warn"`)
+	require.NotContains(t, out, "healthz")
+}
+
+func TestFilters(t *testing.T) {
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, "hello world", 0)
+	r.AddAttrs(slog.String("a", "1"), slog.Group("g", slog.Int("b", 2)))
+
+	require.True(t, HasAttr("a")(context.Background(), r))
+	require.False(t, HasAttr("b")(context.Background(), r))
+	require.True(t, AttrEquals("a", 1)(context.Background(), r))
+	require.True(t, MessageContains("world")(context.Background(), r))
+	require.True(t, And()(context.Background(), r))
+	require.False(t, Or()(context.Background(), r))
+	require.False(t, And(HasAttr("a"), HasAttr("b"))(context.Background(), r))
+	require.True(t, Or(HasAttr("a"), HasAttr("b"))(context.Background(), r))
+	require.False(t, LevelAtLeast(slog.LevelWarn)(context.Background(), r))
+}
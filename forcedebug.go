@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type forceDebugKey struct{}
+
+// ContextWithForceDebug returns a copy of ctx asking for records logged with it
+// to be logged down to the debug level, whatever the level of the logger, so
+// that a single request can be followed in detail in production. Middleware
+// sets it for requests with the header set by WithDebugHeader.
+func ContextWithForceDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceDebugKey{}, true)
+}
+
+// ForceDebugFromContext reports whether ctx was returned by
+// ContextWithForceDebug.
+func ForceDebugFromContext(ctx context.Context) bool {
+	forced, _ := ctx.Value(forceDebugKey{}).(bool)
+	return forced
+}
+
+// ForceDebug returns a logger that logs down to the debug level, whatever its
+// level, while the level of l is left unchanged.
+func (l *L) ForceDebug() *L {
+	if l == nil {
+		return nil
+	}
+
+	c := l.clone()
+	c.forceDebug = true
+	return c
+}
+
+// debugForced reports whether a record at lvl logged with ctx is logged
+// regardless of the level, because debug logging was forced for the logger or
+// the context.
+func (l *L) debugForced(ctx context.Context, lvl slog.Level) bool {
+	if l == nil || l.muted || lvl < slog.LevelDebug {
+		return false
+	}
+	return l.forceDebug || ctx != nil && ForceDebugFromContext(ctx)
+}
+
+// debugRequested reports whether r asks for debug logging with the header set
+// by WithDebugHeader, and is allowed to.
+func (l *L) debugRequested(r *http.Request) bool {
+	if l.debugHeader == "" || l.debugAuthorize == nil {
+		return false
+	}
+	on, err := strconv.ParseBool(r.Header.Get(l.debugHeader))
+	return err == nil && on && l.debugAuthorize(r)
+}
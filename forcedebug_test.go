@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForceDebug(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("warn"), WithCaller(false))
+
+	l.ForceDebug().Debug("forced")
+	l.ForceDebug().New("sub").Info("forced sub")
+	l.Debug("not forced")
+	l.DebugCtx(ContextWithForceDebug(context.Background()), "forced ctx")
+	l.DebugCtx(context.Background(), "not forced ctx")
+
+	require.Contains(t, buf.String(), "msg=forced ")
+	require.Contains(t, buf.String(), "msg=\"forced sub\"")
+	require.Contains(t, buf.String(), "msg=\"forced ctx\"")
+	require.NotContains(t, buf.String(), "not forced")
+
+	require.False(t, ForceDebugFromContext(context.Background()))
+	require.True(t, ForceDebugFromContext(ContextWithForceDebug(context.Background())))
+
+	var nl *L
+	require.Nil(t, nl.ForceDebug())
+}
+
+func TestWithDebugHeader(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithCaller(false),
+		WithDebugHeader("X-Debug-Log", func(r *http.Request) bool {
+			return r.Header.Get("Authorization") == "Bearer ops"
+		}),
+	)
+
+	other := New(WithDestination(&buf), WithLevel("info"), WithCaller(false), WithName("other"))
+	h := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Debug("details")
+		other.DebugCtx(r.Context(), "other details")
+	}))
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		logged bool
+	}{
+		{"authorized", map[string]string{"X-Debug-Log": "1", "Authorization": "Bearer ops"}, true},
+		{"true", map[string]string{"X-Debug-Log": "true", "Authorization": "Bearer ops"}, true},
+		{"unauthorized", map[string]string{"X-Debug-Log": "1", "Authorization": "Bearer guest"}, false},
+		{"off", map[string]string{"X-Debug-Log": "0", "Authorization": "Bearer ops"}, false},
+		{"invalid", map[string]string{"X-Debug-Log": "yes please", "Authorization": "Bearer ops"}, false},
+		{"missing", map[string]string{"Authorization": "Bearer ops"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), r)
+			require.Equal(t, tt.logged, strings.Contains(buf.String(), "msg=details"))
+			require.Equal(t, tt.logged, strings.Contains(buf.String(), "msg=\"other details\""))
+		})
+	}
+
+	// Later requests aren't affected.
+	buf.Reset()
+	l.Debug("after")
+	require.Empty(t, buf.String())
+}
@@ -2,10 +2,7 @@ module github.com/jasonhancock/go-logger
 
 go 1.21
 
-require (
-	github.com/go-stack/stack v1.8.1
-	github.com/stretchr/testify v1.9.0
-)
+require github.com/stretchr/testify v1.9.0
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
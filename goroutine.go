@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// taskIDKey is the attribute identifying goroutines started with Go.
+const taskIDKey = "task_id"
+
+// Go runs fn in a new goroutine, passing it a logger carrying a task_id
+// attribute unique to the goroutine. A panic in fn is handled by Recover.
+func (l *L) Go(fn func(l *L)) {
+	tl := l.With(taskIDKey, newTaskID())
+	go func() {
+		defer Recover(tl)
+		fn(tl)
+	}()
+}
+
+// newTaskID returns a short random identifier.
+func newTaskID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
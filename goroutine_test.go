@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerGo(t *testing.T) {
+	buf := &syncBuffer{}
+	l := New(WithDestination(buf), WithLevel("info"), WithCaller(false))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	l.Go(func(l *L) {
+		defer wg.Done()
+		l.Info("working")
+	})
+	l.Go(func(l *L) {
+		defer wg.Done()
+		panic("boom")
+	})
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		return regexp.MustCompile(`msg="panic in goroutine"`).MatchString(buf.String())
+	}, time.Second, 5*time.Millisecond)
+
+	out := buf.String()
+	require.Regexp(t, `msg=working src=go-logger.test task_id=[0-9a-f]{16}\n`, out)
+	require.Regexp(t, `level=err msg="panic in goroutine" src=go-logger.test task_id=[0-9a-f]{16} panic=boom stack=".*goroutine_test.go`, out)
+}
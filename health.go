@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HealthChecker is implemented by destinations that can report whether they
+// are delivering records, such as Spool, Shipper, and the cloud writers.
+type HealthChecker interface {
+	// Health returns an error describing why records aren't being delivered,
+	// or nil if they are.
+	Health() error
+}
+
+// Healthy returns an error if the logger is failing to deliver records: the
+// last write to the destination failed, or a destination implementing
+// HealthChecker, such as a Spool, reports a problem.
+func (l *L) Healthy() error {
+	if l == nil {
+		return nil
+	}
+
+	var errs []error
+	if l.stats != nil {
+		if err := l.stats.lastErr.Load(); err != nil {
+			errs = append(errs, fmt.Errorf("writing to destination: %w", *err))
+		}
+	}
+	for _, d := range l.dests {
+		if hc, ok := d.w.(HealthChecker); ok {
+			if err := hc.Health(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", d.name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HealthHandler returns an http.Handler for readiness probes, responding with
+// 200 OK if Healthy returns nil and 503 Service Unavailable with the error
+// otherwise.
+func (l *L) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := l.Healthy(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, "ok\n")
+	})
+}
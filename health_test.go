@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerHealthy(t *testing.T) {
+	w := &flakyWriter{}
+	spool, err := NewSpool(w, SpoolConfig{Dir: t.TempDir(), RetryInterval: time.Hour})
+	require.NoError(t, err)
+	defer spool.Close()
+
+	l := New(WithDestination(spool), WithLevel("info"), WithAuditDestination(spool))
+	check := func(status int) string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		l.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, status, rec.Code)
+		return rec.Body.String()
+	}
+
+	l.Info("foo")
+	require.NoError(t, l.Healthy())
+	require.Equal(t, "ok\n", check(http.StatusOK))
+
+	w.setDown(true)
+	l.Info("bar")
+	err = l.Healthy()
+	require.Error(t, err)
+	require.Regexp(t, `^destination: \d+ bytes queued, last write failed: connection refused$`, err.Error())
+	require.Equal(t, err.Error()+"\n", check(http.StatusServiceUnavailable))
+
+	w.setDown(false)
+	spool.replay()
+	require.NoError(t, l.Healthy())
+}
+
+func TestLoggerHealthyWriteErrors(t *testing.T) {
+	w := &flakyWriter{down: true}
+	l := New(WithDestination(w), WithLevel("info"))
+
+	l.Info("foo")
+	require.EqualError(t, l.Healthy(), "writing to destination: connection refused")
+
+	w.setDown(false)
+	l.Info("bar")
+	require.NoError(t, l.Healthy())
+	require.NoError(t, Nop().Healthy())
+}
+
+func TestBatcherHealth(t *testing.T) {
+	errSend := errors.New("throttled")
+	b := &batcher{
+		send:     func(context.Context, []batchRecord) error { return errSend },
+		interval: time.Millisecond,
+	}
+	b.start()
+	defer b.Close()
+
+	_, err := b.Write([]byte("foo\n"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return b.Health() != nil }, time.Second, time.Millisecond)
+	require.ErrorIs(t, b.Health(), errSend)
+}
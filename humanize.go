@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// DurationFormat specifies how time.Duration values are rendered.
+type DurationFormat int
+
+// Duration formats.
+const (
+	// DurationDefault leaves durations to the output format: human readable
+	// strings such as 1.2s in logfmt and integer nanoseconds in JSON.
+	DurationDefault DurationFormat = iota
+	// DurationString renders durations as human readable strings such as 1.2s.
+	DurationString
+	// DurationSeconds renders durations as a floating point number of seconds.
+	DurationSeconds
+	// DurationMillis renders durations as a floating point number of
+	// milliseconds.
+	DurationMillis
+)
+
+// formatDurations returns a ReplaceAttr function rendering durations in format
+// f, or nil if they are left to the output format.
+func formatDurations(f DurationFormat) replaceAttrFunc {
+	var conv func(time.Duration) slog.Value
+	switch f {
+	case DurationString:
+		conv = func(d time.Duration) slog.Value { return slog.StringValue(d.String()) }
+	case DurationSeconds:
+		conv = func(d time.Duration) slog.Value { return slog.Float64Value(d.Seconds()) }
+	case DurationMillis:
+		conv = func(d time.Duration) slog.Value { return slog.Float64Value(float64(d) / float64(time.Millisecond)) }
+	default:
+		return nil
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() == slog.KindDuration {
+			a.Value = conv(a.Value.Duration())
+		}
+		return a
+	}
+}
+
+// ByteSize is a number of bytes. It is rendered as a human readable size using
+// binary units, such as 4.2MiB, in text formats and as a plain number in JSON.
+type ByteSize int64
+
+// Bytes returns n as a ByteSize for logging.
+func Bytes(n int64) ByteSize {
+	return ByteSize(n)
+}
+
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// String returns the size using the largest binary unit in which it is at least
+// 1, with one decimal place.
+func (b ByteSize) String() string {
+	n := int64(b)
+	if n < 0 {
+		return "-" + ByteSize(-n).String()
+	}
+	if n < 1024 {
+		return strconv.FormatInt(n, 10) + "B"
+	}
+
+	v := float64(n)
+	i := 0
+	for v >= 1024 && i < len(byteUnits)-1 {
+		v /= 1024
+		i++
+	}
+
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	if len(s) > 2 && s[len(s)-2:] == ".0" {
+		s = s[:len(s)-2]
+	}
+	return s + byteUnits[i]
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0B"},
+		{1023, "1023B"},
+		{1024, "1KiB"},
+		{1536, "1.5KiB"},
+		{4404019, "4.2MiB"},
+		{5 << 30, "5GiB"},
+		{-2048, "-2KiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			require.Equal(t, tt.want, Bytes(tt.n).String())
+		})
+	}
+}
+
+func TestLoggerHumanize(t *testing.T) {
+	tests := []struct {
+		desc     string
+		format   string
+		dur      DurationFormat
+		expected string
+	}{
+		{"logfmt default", FormatLogFmt, DurationDefault, "d=1.5s size=4.2MiB\n"},
+		{"json default", FormatJSON, DurationDefault, `"d":1500000000,"size":4404019}`},
+		{"json string", FormatJSON, DurationString, `"d":"1.5s","size":4404019}`},
+		{"json seconds", FormatJSON, DurationSeconds, `"d":1.5,"size":4404019}`},
+		{"logfmt millis", FormatLogFmt, DurationMillis, "d=1500 size=4.2MiB\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := New(
+				WithDestination(&buf),
+				WithFormat(tt.format),
+				WithLevel("info"),
+				WithCaller(false),
+				WithDurationFormat(tt.dur),
+			)
+			l.Info("foo", "d", 1500*time.Millisecond, "size", Bytes(4404019))
+			require.Contains(t, buf.String(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package logger
+
+// Logger is the set of logging methods implemented by *L. Libraries can accept
+// a Logger rather than *L so that callers may substitute their own
+// implementation, such as a mock in tests.
+//
+// With and New return the concrete *L so that *L satisfies the interface.
+// Implementations that don't wrap an *L can return Nop.
+type Logger interface {
+	Debug(msg any, keyvals ...any)
+	Info(msg any, keyvals ...any)
+	Warn(msg any, keyvals ...any)
+	Err(msg any, keyvals ...any)
+	Fatal(msg any, keyvals ...any)
+	LogError(msg string, err error, keyvals ...any)
+	With(keyvals ...any) *L
+	New(name string) *L
+}
+
+var _ Logger = (*L)(nil)
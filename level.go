@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// Level is a logging level that is rendered and parsed using the names the
+// logger uses, such as info or err. It implements slog.Leveler, so it can be
+// used wherever a slog.Level is expected, and encoding.TextMarshaler,
+// encoding.TextUnmarshaler and flag.Value, so it can be read from
+// configuration files and command line flags:
+//
+//	lvl := logger.Level(slog.LevelInfo)
+//	flag.Var(&lvl, "log-level", "log level")
+type Level slog.Level
+
+// Level returns l as a slog.Level.
+func (l Level) Level() slog.Level {
+	return slog.Level(l)
+}
+
+// String returns the name of the level.
+func (l Level) String() string {
+	return levelLabel(slog.Level(l))
+}
+
+// MarshalText returns the name of the level.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText sets l to the level named by text, as parsed by ParseLevel. It
+// returns an error if text does not name a level.
+func (l *Level) UnmarshalText(text []byte) error {
+	lvl, ok := parseLevel(string(text), false)
+	if !ok {
+		return unknownLevelError("level", string(text))
+	}
+	*l = Level(lvl)
+	return nil
+}
+
+// Set sets l to the level named by s. It implements flag.Value.
+func (l *Level) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
+
+// ParseLevelExact parses the name of a level, ignoring case. Unlike ParseLevel,
+// it doesn't accept abbreviations, and returns an error listing the valid names
+// if s doesn't name a level.
+func ParseLevelExact(s string) (slog.Level, error) {
+	lvl, ok := parseLevel(s, true)
+	if !ok {
+		return lvl, unknownLevelError("level", s)
+	}
+	return lvl, nil
+}
+
+// LevelNames returns the names of the levels recognized by ParseLevel, including
+// any added with RegisterLevel, from the least to the most severe.
+func LevelNames() []string {
+	names := *levelNames.Load()
+	levels := make([]slog.Level, 0, len(names))
+	for lvl := range names {
+		levels = append(levels, lvl)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	s := make([]string, len(levels))
+	for i, lvl := range levels {
+		s[i] = names[lvl]
+	}
+	return s
+}
+
+// parseLevel returns the level named by s, or, unless exact is set, the only
+// level whose name s is a prefix of. It reports whether s was recognized.
+func parseLevel(s string, exact bool) (slog.Level, bool) {
+	s = strings.ToLower(s)
+	if s == "" {
+		return LevelAll, false
+	}
+
+	match, matches := LevelAll, 0
+	for lvl, name := range *levelNames.Load() {
+		if name == s {
+			return lvl, true
+		}
+		if !exact && strings.HasPrefix(name, s) {
+			match = lvl
+			matches++
+		}
+	}
+	if matches != 1 {
+		return LevelAll, false
+	}
+	return match, true
+}
+
+// validLevel reports whether s is recognized by ParseLevel.
+func validLevel(s string) bool {
+	_, ok := parseLevel(s, false)
+	return ok
+}
+
+// unknownLevelError returns the error for an unrecognized level, where what
+// describes the level, such as "caller level".
+func unknownLevelError(what, s string) error {
+	return fmt.Errorf("logger: unknown %s %q, expected one of %s", what, s, strings.Join(LevelNames(), ", "))
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevel(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		require.Equal(t, "info", Level(slog.LevelInfo).String())
+		require.Equal(t, "err", Level(slog.LevelError).String())
+		require.Equal(t, "fatal", Level(LevelFatal).String())
+		require.Equal(t, slog.LevelWarn, Level(slog.LevelWarn).Level())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		type config struct {
+			Level Level `json:"level"`
+		}
+
+		var cfg config
+		require.NoError(t, json.Unmarshal([]byte(`{"level":"warn"}`), &cfg))
+		require.Equal(t, Level(slog.LevelWarn), cfg.Level)
+
+		b, err := json.Marshal(cfg)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"level":"warn"}`, string(b))
+
+		require.EqualError(t, json.Unmarshal([]byte(`{"level":"loud"}`), &cfg), `logger: unknown level "loud", expected one of all, debug, info, warn, err, fatal`)
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		lvl := Level(slog.LevelInfo)
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(&lvl, "log-level", "log level")
+
+		require.NoError(t, fs.Parse([]string{"-log-level", "debug"}))
+		require.Equal(t, Level(slog.LevelDebug), lvl)
+		require.Equal(t, "debug", fs.Lookup("log-level").Value.String())
+		require.Error(t, lvl.Set(""))
+	})
+}
+
+func TestRegisterLevel(t *testing.T) {
+	prev := levelNames.Load()
+	t.Cleanup(func() { levelNames.Store(prev) })
+
+	const (
+		levelNotice   = slog.Level(2)
+		levelSecurity = slog.Level(10)
+	)
+	require.NoError(t, RegisterLevel(levelNotice, "Notice"))
+	require.NoError(t, RegisterLevel(levelSecurity, "security"))
+	require.EqualError(t, RegisterLevel(LevelAudit, "notice"), "logger: level 2147483647 is reserved for audit")
+	require.EqualError(t, RegisterLevel(LevelAll, "trace"), "logger: level -10 is reserved for all")
+
+	require.Equal(t, levelNotice, ParseLevel("notice").Level())
+	require.Equal(t, levelSecurity, ParseLevel("SECURITY").Level())
+	require.Equal(t, "security", Level(levelSecurity).String())
+	require.True(t, validLevel("notice"))
+
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("notice"), WithCaller(false), WithKeyNames(KeyNames{Time: KeyOmit}), WithNumericLevel(NumericLevelSyslog, "severity"))
+	l.Info("dropped")
+	l.Log(context.Background(), levelNotice, "config reloaded")
+	l.Log(context.Background(), levelSecurity, "login failed")
+	require.NoError(t, l.Audit("login", "alice", "app"))
+	require.Equal(t, `level=notice msg="config reloaded" src=go-logger.test severity=5
+level=security msg="login failed" src=go-logger.test severity=3
+level=audit msg=login src=go-logger.test actor=alice target=app
+`, buf.String())
+}
+
+func TestParseLevel(t *testing.T) {
+	prev := levelNames.Load()
+	t.Cleanup(func() { levelNames.Store(prev) })
+	require.NoError(t, RegisterLevel(slog.Level(16), "emergency"))
+	require.NoError(t, RegisterLevel(slog.Level(3), "in"))
+
+	tests := []struct {
+		in    string
+		want  slog.Level
+		exact bool
+	}{
+		{"info", slog.LevelInfo, true},
+		{"INFO", slog.LevelInfo, true},
+		{"inf", slog.LevelInfo, false},
+		{"in", slog.Level(3), true},
+		{"w", slog.LevelWarn, false},
+		{"er", slog.LevelError, false},
+		{"em", slog.Level(16), false},
+		{"e", LevelAll, false},
+		{"", LevelAll, false},
+		{"verbose", LevelAll, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			// Repeat to catch any dependence on map iteration order.
+			for i := 0; i < 20; i++ {
+				require.Equal(t, tt.want, ParseLevel(tt.in).Level())
+			}
+
+			lvl, err := ParseLevelExact(tt.in)
+			if !tt.exact {
+				require.EqualError(t, err, fmt.Sprintf("logger: unknown level %q, expected one of all, debug, info, in, warn, err, fatal, emergency", tt.in))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, lvl)
+		})
+	}
+}
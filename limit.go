@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// truncatedKey is the attribute added to records that had attributes dropped.
+const truncatedKey = "_truncated"
+
+// limitBuffer is placed between a handler and its destination so that each
+// serialized record can be measured before it is written.
+type limitBuffer struct {
+	mu   sync.Mutex
+	buf  bytes.Buffer
+	dest io.Writer
+}
+
+func (b *limitBuffer) Write(p []byte) (int, error) {
+	return b.buf.Write(p)
+}
+
+// limitHandler enforces caps on the number of attributes and serialized size of
+// each record. Attributes whose keys are listed in protect are never dropped.
+// The size is measured by passing the record to inner, possibly several times,
+// so tee, if not nil, is passed the final record once afterwards.
+type limitHandler struct {
+	inner    slog.Handler
+	tee      slog.Handler
+	buf      *limitBuffer
+	maxSize  int
+	maxAttrs int
+	protect  []string
+}
+
+func (h *limitHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.inner.Enabled(ctx, lvl)
+}
+
+func (h *limitHandler) Handle(ctx context.Context, r slog.Record) error {
+	pooled := getAttrs()
+	defer putAttrs(pooled)
+	attrs := *pooled
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	*pooled = attrs
+
+	keep := len(attrs)
+	if h.maxAttrs > 0 && h.droppable(attrs) > h.maxAttrs {
+		keep = h.maxAttrs
+	}
+
+	if h.buf == nil {
+		nr := h.record(r, attrs, keep)
+		return errors.Join(h.inner.Handle(ctx, nr), h.handleTee(ctx, nr))
+	}
+
+	keep, err := h.handleLimited(ctx, r, attrs, keep)
+	if err != nil {
+		return err
+	}
+	return h.handleTee(ctx, h.record(r, attrs, keep))
+}
+
+// handleLimited passes r to inner, dropping attributes until it fits in maxSize,
+// and writes the result to the destination. It returns the number of attributes
+// kept.
+func (h *limitHandler) handleLimited(ctx context.Context, r slog.Record, attrs []slog.Attr, keep int) (int, error) {
+	h.buf.mu.Lock()
+	defer h.buf.mu.Unlock()
+
+	for {
+		h.buf.buf.Reset()
+		if err := h.inner.Handle(ctx, h.record(r, attrs, keep)); err != nil {
+			return keep, err
+		}
+		if h.buf.buf.Len() <= h.maxSize || keep == 0 {
+			break
+		}
+		if keep > h.droppable(attrs) {
+			keep = h.droppable(attrs)
+		}
+		keep--
+	}
+
+	_, err := h.buf.dest.Write(h.buf.buf.Bytes())
+	return keep, err
+}
+
+func (h *limitHandler) handleTee(ctx context.Context, r slog.Record) error {
+	if h.tee == nil {
+		return nil
+	}
+	return h.tee.Handle(ctx, r)
+}
+
+// droppable returns the number of attributes that are not protected.
+func (h *limitHandler) droppable(attrs []slog.Attr) int {
+	var n int
+	for _, a := range attrs {
+		if !slices.Contains(h.protect, a.Key) {
+			n++
+		}
+	}
+	return n
+}
+
+// record returns a copy of r containing the first keep unprotected attributes
+// and all of the protected ones.
+func (h *limitHandler) record(r slog.Record, attrs []slog.Attr, keep int) slog.Record {
+	if keep >= h.droppable(attrs) {
+		return r
+	}
+
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	var n int
+	for _, a := range attrs {
+		if slices.Contains(h.protect, a.Key) {
+			nr.AddAttrs(a)
+			continue
+		}
+		if n < keep {
+			nr.AddAttrs(a)
+			n++
+		}
+	}
+	nr.AddAttrs(slog.Bool(truncatedKey, true))
+
+	return nr
+}
+
+func (h *limitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.inner = h.inner.WithAttrs(attrs)
+	if h.tee != nil {
+		c.tee = h.tee.WithAttrs(attrs)
+	}
+	return &c
+}
+
+func (h *limitHandler) WithGroup(name string) slog.Handler {
+	c := *h
+	c.inner = h.inner.WithGroup(name)
+	if h.tee != nil {
+		c.tee = h.tee.WithGroup(name)
+	}
+	return &c
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerMaxAttrs(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithMaxAttrs(2),
+	)
+
+	t.Run("under limit", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.Info("foo", "key1", "value1", "key2", "value2")
+		require.Contains(t, buf.String(), "key2=value2")
+		require.NotContains(t, buf.String(), truncatedKey)
+	})
+
+	t.Run("over limit", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.Info("foo", "key1", "value1", "key2", "value2", "key3", "value3")
+		require.Contains(t, buf.String(), "key1=value1")
+		require.Contains(t, buf.String(), "key2=value2")
+		require.NotContains(t, buf.String(), "key3")
+		require.Contains(t, buf.String(), "_truncated=true")
+		require.Contains(t, buf.String(), "caller=")
+	})
+}
+
+func TestLoggerMaxAttrsProtected(t *testing.T) {
+	t.Run("sequence", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(WithDestination(&buf), WithLevel("info"), WithMaxAttrs(1), WithSequence(""))
+		l.Info("foo", "a", 1, "b", 2)
+		require.Contains(t, buf.String(), "a=1")
+		require.NotContains(t, buf.String(), "b=2")
+		require.Contains(t, buf.String(), "seq=1")
+		require.Contains(t, buf.String(), "_truncated=true")
+	})
+
+	t.Run("record id", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(WithDestination(&buf), WithLevel("info"), WithMaxAttrs(1), WithRecordIDs())
+		l.Info("foo", "a", 1, "b", 2)
+		require.Contains(t, buf.String(), "a=1")
+		require.NotContains(t, buf.String(), "b=2")
+		require.Regexp(t, `record_id=[0-9A-Z]{26}`, buf.String())
+		require.Contains(t, buf.String(), "_truncated=true")
+	})
+}
+
+func TestLoggerMaxRecordSize(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithMaxRecordSize(300),
+	)
+
+	t.Run("under limit", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.Info("foo", "key1", "value1")
+		require.Contains(t, buf.String(), "key1=value1")
+		require.NotContains(t, buf.String(), truncatedKey)
+	})
+
+	t.Run("over limit", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.Info("foo", "key1", "value1", "big", strings.Repeat("x", 500), "key3", "value3")
+		require.LessOrEqual(t, buf.Len(), 300)
+		require.Contains(t, buf.String(), "key1=value1")
+		require.NotContains(t, buf.String(), "big=")
+		require.NotContains(t, buf.String(), "key3")
+		require.Contains(t, buf.String(), "_truncated=true")
+		require.Contains(t, buf.String(), "caller=")
+		require.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	})
+}
+
+func TestLoggerMaxRecordSizeTee(t *testing.T) {
+	var buf, tee, other bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithMaxRecordSize(200),
+		WithTee(&tee, FormatJSON),
+		WithHandler(slog.NewTextHandler(&other, nil)),
+	).With("component", "db")
+
+	l.Info("foo", "key1", "value1", "big", strings.Repeat("x", 500), "key3", "value3")
+	require.LessOrEqual(t, buf.Len(), 200)
+	require.Equal(t, 1, strings.Count(tee.String(), "\n"))
+	require.Contains(t, tee.String(), `"component":"db"`)
+	require.Contains(t, tee.String(), `"key1":"value1"`)
+	require.Contains(t, tee.String(), `"_truncated":true`)
+	require.NotContains(t, tee.String(), "big")
+	require.Equal(t, 1, strings.Count(other.String(), "\n"))
+}
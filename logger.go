@@ -2,27 +2,38 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/go-stack/stack"
 )
 
 // Constants defining various output formats.
 const (
-	FormatLogFmt = "logfmt"
-	FormatJSON   = "json"
+	FormatLogFmt      = "logfmt"
+	FormatJSON        = "json"
+	FormatJSONPretty  = "json-pretty"
+	FormatMsgpack     = "msgpack"
+	FormatCloudEvents = "cloudevents"
 )
 
 // AvailableFormats lists the available format types.
 var AvailableFormats = []string{
 	FormatLogFmt,
 	FormatJSON,
+	FormatJSONPretty,
+	FormatMsgpack,
+	FormatCloudEvents,
 }
 
 const (
@@ -30,75 +41,266 @@ const (
 	LevelFatal = slog.Level(12)
 )
 
-var levelNames = map[slog.Leveler]string{
-	LevelAll:        "all",
-	LevelFatal:      "fatal",
-	slog.LevelError: "err",
-	slog.LevelWarn:  "warn",
-	slog.LevelInfo:  "info",
-	slog.LevelDebug: "debug",
+// levelNames maps levels to the names they are rendered with. It is replaced
+// rather than modified by RegisterLevel so it can be read without locking.
+var levelNames atomic.Pointer[map[slog.Level]string]
+
+// levelNamesMu serializes RegisterLevel.
+var levelNamesMu sync.Mutex
+
+func init() {
+	levelNames.Store(&map[slog.Level]string{
+		LevelAll:        "all",
+		LevelFatal:      "fatal",
+		slog.LevelError: "err",
+		slog.LevelWarn:  "warn",
+		slog.LevelInfo:  "info",
+		slog.LevelDebug: "debug",
+	})
 }
 
-// ParseLevel parses the string into a Level.
-func ParseLevel(s string) slog.Leveler {
-	s = strings.ToLower(s)
-	for l, name := range levelNames {
-		if strings.HasPrefix(name, s) {
-			return l
-		}
+// RegisterLevel names an additional level, such as notice or security, so that
+// it is rendered as name and parsed by ParseLevel. Registering a level that
+// already has a name renames it. Sinks that map levels onto their own
+// severities, such as NumericLevelSyslog, map a registered level to that of the
+// closest built in level below it. LevelAll and LevelAudit are reserved, and
+// registering them returns an error.
+func RegisterLevel(level slog.Level, name string) error {
+	if level == LevelAll || level == LevelAudit {
+		return fmt.Errorf("logger: level %d is reserved for %s", int(level), levelLabel(level))
+	}
+
+	levelNamesMu.Lock()
+	defer levelNamesMu.Unlock()
+
+	names := make(map[slog.Level]string, len(*levelNames.Load())+1)
+	for l, n := range *levelNames.Load() {
+		names[l] = n
 	}
-	return LevelAll
+	names[level] = strings.ToLower(name)
+	levelNames.Store(&names)
+	return nil
 }
 
-// L is the logger implementation
+// LevelName returns the name a level is rendered with, such as info or err.
+func LevelName(level slog.Level) string {
+	return levelLabel(level)
+}
+
+// levelLabel returns the name used to render a level.
+func levelLabel(level slog.Level) string {
+	if level == LevelAudit {
+		return "audit"
+	}
+	if name, exists := (*levelNames.Load())[level]; exists {
+		return name
+	}
+	return level.String()
+}
+
+// NumericLevel specifies how a level is rendered as a number.
+type NumericLevel int
+
+// Numeric level representations.
+const (
+	// NumericLevelNone renders levels using only their names.
+	NumericLevelNone NumericLevel = iota
+	// NumericLevelSlog renders levels as their slog.Level integer value.
+	NumericLevelSlog
+	// NumericLevelSyslog renders levels as RFC 5424 syslog severities.
+	NumericLevelSyslog
+)
+
+func (n NumericLevel) value(lvl slog.Level) int64 {
+	if n == NumericLevelSlog {
+		return int64(lvl)
+	}
+
+	switch {
+	case lvl >= LevelFatal:
+		return 2 // critical
+	case lvl >= slog.LevelError:
+		return 3 // error
+	case lvl >= slog.LevelWarn:
+		return 4 // warning
+	case lvl > slog.LevelInfo:
+		return 5 // notice
+	case lvl == slog.LevelInfo:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
+
+// ParseLevel parses the string into a Level. Case is ignored, and s may be
+// abbreviated to any prefix of a level's name that no other name shares, so "w"
+// parses as warn. A name matching s exactly is preferred over one it is a prefix
+// of. Strings that are empty, ambiguous or match no level parse as LevelAll; use
+// ParseLevelExact to detect them.
+func ParseLevel(s string) slog.Leveler {
+	lvl, _ := parseLevel(s, false)
+	return lvl
+}
+
+// L is the logger implementation. A nil *L is a valid logger that discards
+// everything logged to it: all of its methods are safe to call, and the methods
+// deriving new loggers, such as With and New, return nil.
 type L struct {
+	base             *slog.Logger
+	auditBase        *slog.Logger
 	slogger          *slog.Logger
+	audit            *slog.Logger
+	attrs            []slog.Attr
 	src              []string
 	showCaller       bool
 	callerPrefixTrim string
+	callerSkip       int
+	callerFormat     CallerFormat
+	callerMinLevel   slog.Level
+	now              func() time.Time
+	keys             KeyNames
+	numericLevel     NumericLevel
+	numericLevelKey  string
+	seqKey           string
+	seq              *atomic.Uint64
+	recordIDKey      string
+	srcPathKey       string
+	onceKey          string
+	extractors       []ContextExtractor
+	dedupeAttrs      bool
+	strictKeys       bool
+	strictPanic      bool
+	closers          []io.Closer
+	srcFilter        *sourceFilter
+	muted            bool
+	filters          []Filter
+	registry         *Registry
+	level            *slog.LevelVar
+	config           *config
+	errorCodes       bool
+	errorVerbose     bool
+	recordSinks      []func(slog.Record)
+	namedSinks       map[string]slog.Handler
+	to               []string
+	routes           []route
+	sampler          *levelSampler
+	adaptive         *adaptiveSampler
+	traceSampled     func(context.Context) bool
+	forceDebug       bool
+	debugHeader      string
+	debugAuthorize   func(*http.Request) bool
+	repanic          bool
+	dumpOnFatal      bool
+	dumpWriter       io.Writer
+	stats            *stats
+	dests            []destination
 }
 
 // New initializes a new logger. If w is nil, logs will be sent to stdout.
+// Invalid options fall back to defaults; use NewE to have them reported.
 func New(opts ...Option) *L {
+	return newLogger(newOptions(opts))
+}
+
+// NewE initializes a new logger like New, but returns an error describing
+// every invalid option, such as an unknown format or level or options that
+// can't be combined, instead of falling back to defaults.
+func NewE(opts ...Option) (*L, error) {
+	opt := newOptions(opts)
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return newLogger(opt), nil
+}
+
+// MustNew is like NewE but panics if the options are invalid.
+func MustNew(opts ...Option) *L {
+	l, err := NewE(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+func newOptions(opts []Option) *options {
 	opt := &options{
 		destination: os.Stdout,
 		name:        filepath.Base(os.Args[0]),
 		showCaller:  true,
+		callerLevel: "all",
+		clock:       time.Now,
 	}
 
 	for _, o := range opts {
 		o(opt)
 	}
+	return opt
+}
 
-	if opt.timeFormatter == nil {
-		// Detect if the current Location is UTC or not. If not, install the formatter.
-		// This is an optimization because servers should be set to UTC.
-		ts := time.Now()
-		if ts.Format(time.RFC3339Nano) != ts.In(time.UTC).Format(time.RFC3339Nano) {
-			opt.timeFormatter = func(ts time.Time) string {
-				return ts.In(time.UTC).Format(time.RFC3339Nano)
-			}
-		}
+func newLogger(opt *options) *L {
+	keys := opt.keys.withDefaults()
+	if keys.Caller == KeyOmit {
+		opt.showCaller = false
 	}
 
+	timeFormatter := opt.timeFormatter()
+
 	var l *slog.Logger
 
+	// Levels are variable so they can be changed at runtime. With a registry,
+	// they are checked by the logger itself so they can differ between
+	// sub-loggers sharing a handler.
+	var level *slog.LevelVar
+	var handlerLevel slog.Leveler
+	if opt.registry != nil {
+		level = opt.registry.rootLevel(ParseLevel(opt.level).Level())
+		handlerLevel = LevelAll
+	} else {
+		level = new(slog.LevelVar)
+		level.Set(ParseLevel(opt.level).Level())
+		handlerLevel = level
+	}
+
 	handlerOpts := slog.HandlerOptions{
-		Level: ParseLevel(opt.level),
+		Level: handlerLevel,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+
+			// Attributes passed by callers can use the same keys as the built-in
+			// ones, so only values of the built-in type are treated as such.
 			switch a.Key {
 			case slog.TimeKey:
-				a.Key = "ts"
-				if opt.timeFormatter != nil {
-					a.Value = slog.StringValue(opt.timeFormatter(a.Value.Time()))
+				if a.Value.Kind() != slog.KindTime {
+					break
+				}
+				if keys.Time == KeyOmit {
+					return slog.Attr{}
+				}
+				a.Key = keys.Time
+				if timeFormatter != nil {
+					a.Value = timeFormatter(a.Value.Time())
 				}
 			case slog.LevelKey:
-				level := a.Value.Any().(slog.Level)
-				levelLabel, exists := levelNames[level]
-				if !exists {
-					levelLabel = level.String()
+				level, ok := a.Value.Any().(slog.Level)
+				if !ok {
+					break
+				}
+				if keys.Level == KeyOmit {
+					return slog.Attr{}
+				}
+				a.Key = keys.Level
+				if opt.numericLevel != NumericLevelNone && opt.numericLevelKey == "" {
+					a.Value = slog.Int64Value(opt.numericLevel.value(level))
+					break
 				}
-				a.Value = slog.StringValue(levelLabel)
+				a.Value = slog.StringValue(levelLabel(level))
+			case slog.MessageKey:
+				if keys.Message == KeyOmit {
+					return slog.Attr{}
+				}
+				a.Key = keys.Message
 			default:
 			}
 
@@ -107,55 +309,525 @@ func New(opts ...Option) *L {
 		},
 	}
 
-	switch strings.ToLower(opt.format) {
-	case FormatJSON:
-		l = slog.New(slog.NewJSONHandler(opt.destination, &handlerOpts))
-	default:
-		l = slog.New(slog.NewTextHandler(opt.destination, &handlerOpts))
+	handlerOpts.ReplaceAttr = chainReplaceAttr(
+		handlerOpts.ReplaceAttr,
+		marshalValues(opt.marshalers),
+		formatDurations(opt.durationFormat),
+		sanitizeValues(opt.sanitize),
+		truncateValues(opt.maxValueLength),
+		opt.replaceAttr,
+		guardCardinality(opt.cardinalityLimit, opt.cardinalityMode, opt.cardinalityKeys, []string{
+			keys.Time, keys.Level, keys.Message, keys.Source,
+			keys.Caller, keys.Caller + "_file", keys.Caller + "_func",
+			opt.numericLevelKey, opt.seqKey, opt.recordIDKey, opt.srcPathKey,
+		}, opt.errorHandler),
+	)
+
+	st := &stats{}
+	var closers []io.Closer
+
+	dest := opt.destination
+	if opt.serialize {
+		dest = &lockedWriter{w: dest}
+	}
+	if opt.writeTimeout > 0 {
+		tw := newTimeoutWriter(dest, opt.writeTimeout, st)
+		dest = tw
+		closers = append(closers, tw)
+	}
+	var split *levelSplit
+	if opt.splitDest != nil {
+		high := opt.splitDest
+		if opt.serialize {
+			high = &lockedWriter{w: high}
+		}
+		split = &levelSplit{low: dest, high: high, level: ParseLevel(opt.splitLevel).Level()}
+		dest = split
+	}
+
+	var w io.Writer = &statsWriter{w: dest, stats: st}
+	if opt.encryptionKey != nil {
+		ew, err := newEncryptWriter(opt.encryptionKey, w)
+		if err != nil {
+			// Never fall back to writing plaintext.
+			ew = &encryptWriter{err: err}
+			if opt.errorHandler != nil {
+				opt.errorHandler(err)
+			}
+		}
+		w = ew
+	}
+	if opt.compression != "" {
+		cw, err := newCompressWriter(opt.compression, w)
+		switch {
+		case err == nil:
+			w = cw
+			closers = append(closers, cw)
+		case opt.errorHandler != nil:
+			opt.errorHandler(err)
+		}
+	}
+	if opt.errorHandler != nil || opt.fallback != nil {
+		w = &fallbackWriter{dest: w, fallback: opt.fallback, onError: opt.errorHandler}
 	}
 
-	l = l.With(append(opt.keyvals, slog.String("src", opt.name))...)
+	out := w
+
+	if strings.ToLower(opt.format) == FormatCloudEvents {
+		w = opt.cloudEventsWriter(w, keys)
+	}
+	if opt.multiline && isLogfmt(opt.format) {
+		w = &multilineWriter{w: w, threshold: opt.multilineLen}
+	}
+	if isLogfmt(opt.format) && opt.color.enabled(opt.destination) {
+		w = &colorWriter{w: w, levelKey: keys.Level, messageKey: keys.Message}
+	}
+	if len(opt.keyOrder) > 0 && isLogfmt(opt.format) {
+		w = &keyOrderWriter{w: w, order: opt.keyOrder}
+	}
+
+	var lb *limitBuffer
+	if opt.maxRecordSize > 0 {
+		lb = &limitBuffer{dest: w}
+		w = lb
+	}
+
+	var h slog.Handler
+	if opt.maxRecordSize > 0 || opt.maxAttrs > 0 {
+		// Only the destination's output is measured; tees get the final record.
+		lh := &limitHandler{
+			inner:    newFormatHandler(opt.format, w, &handlerOpts),
+			buf:      lb,
+			maxSize:  opt.maxRecordSize,
+			maxAttrs: opt.maxAttrs,
+			protect:  []string{keys.Caller, keys.Caller + "_file", keys.Caller + "_func", opt.numericLevelKey, opt.seqKey, opt.recordIDKey},
+		}
+		if tees := opt.teeHandlers(handlerOpts, keys); len(tees) > 0 {
+			lh.tee = &fanoutHandler{handlers: tees}
+		}
+		h = lh
+	} else {
+		h = opt.teeHandler(newFormatHandler(opt.format, w, &handlerOpts), handlerOpts, keys)
+	}
+
+	h = &statsHandler{inner: h, stats: st}
+	if split != nil {
+		h = &splitHandler{inner: h, split: split}
+	}
+
+	if opt.dedupWindow > 0 {
+		dh := newDedupHandler(h, opt.dedupWindow, st, opt.seqKey, opt.recordIDKey)
+		h = dh
+		closers = append(closers, dh.state)
+	}
+
+	if opt.sentryDSN != "" {
+		sc, err := newSentryClient(opt.sentryDSN, opt.errorHandler)
+		switch {
+		case err == nil:
+			h = &sentryHandler{inner: h, client: sc, keys: keys}
+			closers = append(closers, sc)
+		case opt.errorHandler != nil:
+			opt.errorHandler(err)
+		}
+	}
+
+	for _, cfg := range opt.notifiers {
+		n := newNotifier(cfg, opt.errorHandler)
+		h = &notifyHandler{inner: h, notifier: n, sourceKey: keys.Source}
+		closers = append(closers, n)
+	}
+
+	l = slog.New(h)
+
+	auditDest := opt.auditDestination
+	if auditDest == nil {
+		auditDest = out
+	}
+	auditFormat := strings.ToLower(opt.format)
+	if opt.auditHashChain {
+		// Each audit event must be on a single line for the chain to be verifiable.
+		if auditFormat == FormatJSONPretty || auditFormat == FormatMsgpack || auditFormat == FormatCloudEvents {
+			auditFormat = FormatJSON
+		}
+		auditDest = &hashChainWriter{
+			w:    auditDest,
+			json: auditFormat == FormatJSON,
+			key:  opt.auditHashKey,
+		}
+	}
+	if auditFormat == FormatCloudEvents {
+		auditDest = opt.cloudEventsWriter(auditDest, keys)
+	}
+	if len(opt.keyOrder) > 0 && isLogfmt(auditFormat) {
+		auditDest = &keyOrderWriter{w: auditDest, order: opt.keyOrder}
+	}
+	auditOpts := handlerOpts
+	auditOpts.Level = LevelAudit
+	auditHandler := newFormatHandler(auditFormat, auditDest, &auditOpts)
+	if opt.auditDestination == nil {
+		// Audit events follow regular records to the tees, but not to the
+		// destination of WithLevelSplit, since they aren't errors.
+		auditHandler = opt.teeHandler(auditHandler, auditOpts, keys)
+	}
+	audit := slog.New(auditHandler)
+
+	var attrs []slog.Attr
+	attrs = append(attrs, toAttrs(opt.keyvals)...)
+	attrs = append(attrs, opt.attrs...)
+	if keys.Source != KeyOmit {
+		attrs = append(attrs, slog.String(keys.Source, opt.name))
+	}
+	if opt.srcPathKey != "" {
+		attrs = append(attrs, slog.Any(opt.srcPathKey, []string{opt.name}))
+	}
+	if opt.dedupeAttrs {
+		attrs = dedupeAttrs(attrs)
+	}
 
 	return &L{
-		slogger:          l,
+		base:             l,
+		auditBase:        audit,
+		slogger:          withAttrs(l, attrs),
+		audit:            withAttrs(audit, attrs),
+		attrs:            attrs,
 		src:              []string{opt.name},
 		showCaller:       opt.showCaller,
 		callerPrefixTrim: opt.callerPrefixTrim,
+		callerSkip:       opt.callerSkip,
+		callerFormat:     opt.callerFormat,
+		callerMinLevel:   ParseLevel(opt.callerLevel).Level(),
+		now:              opt.clock,
+		keys:             keys,
+		numericLevel:     opt.numericLevel,
+		numericLevelKey:  opt.numericLevelKey,
+		seqKey:           opt.seqKey,
+		seq:              opt.seq,
+		recordIDKey:      opt.recordIDKey,
+		srcPathKey:       opt.srcPathKey,
+		extractors:       opt.extractors,
+		dedupeAttrs:      opt.dedupeAttrs,
+		strictKeys:       opt.strictKeys,
+		strictPanic:      opt.strictPanic,
+		closers:          closers,
+		srcFilter:        opt.srcFilter,
+		filters:          opt.filters,
+		registry:         opt.registry,
+		level:            level,
+		config:           newConfig(opt),
+		errorCodes:       opt.errorCodes,
+		errorVerbose:     opt.errorVerbose,
+		recordSinks:      opt.recordSinks,
+		namedSinks:       opt.namedSinkHandlers(handlerOpts, keys),
+		routes:           opt.compileRoutes(),
+		sampler:          newLevelSampler(opt.sampleRates),
+		adaptive:         newAdaptiveSampler(opt.adaptiveRate, opt.adaptiveSummary),
+		traceSampled:     opt.traceSampled,
+		debugHeader:      opt.debugHeader,
+		debugAuthorize:   opt.debugAuthorize,
+		repanic:          opt.repanic,
+		dumpOnFatal:      opt.dumpOnFatal,
+		dumpWriter:       opt.dumpWriter,
+		stats:            st,
+		dests:            opt.destinations(),
+		muted:            opt.srcFilter.muted([]string{opt.name}),
+	}
+}
+
+// newFormatHandler returns a handler writing to w in the specified format. For
+// FormatCloudEvents, w is expected to already wrap records in an envelope.
+func newFormatHandler(format string, w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	switch strings.ToLower(format) {
+	case FormatJSON, FormatCloudEvents:
+		return slog.NewJSONHandler(w, opts)
+	case FormatJSONPretty:
+		return slog.NewJSONHandler(&indentWriter{w: w}, opts)
+	case FormatMsgpack:
+		return newMsgpackHandler(w, opts)
+	default:
+		return slog.NewTextHandler(w, opts)
+	}
+}
+
+// CallerFormat specifies how the caller of each log message is reported.
+type CallerFormat int
+
+// Caller formats.
+const (
+	// CallerFile reports the caller as the package path, file name, and line
+	// number, e.g. github.com/org/repo/pkg/file.go:12.
+	CallerFile CallerFormat = iota
+	// CallerFunc reports the caller as the fully qualified function name, e.g.
+	// github.com/org/repo/pkg.(*Server).handle.
+	CallerFunc
+	// CallerFileAndFunc reports both as separate attributes, using the caller key
+	// suffixed with _file and _func.
+	CallerFileAndFunc
+)
+
+// callerPC returns the program counter from a specified depth in the callstack.
+func callerPC(depth int) uintptr {
+	var pcs [1]uintptr
+	if runtime.Callers(depth+1, pcs[:]) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// callerFrame returns the frame for the program counter returned by callerPC.
+func callerFrame(pc uintptr) runtime.Frame {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return frame
+}
+
+// callerFile returns the frame's file and line, formatted as the fully qualified
+// package path followed by the file's base name and line number.
+func callerFile(frame runtime.Frame, prefixTrim string) string {
+	c := funcPackage(frame.Function) + "/" + filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+	if prefixTrim != "" {
+		return strings.TrimPrefix(c, prefixTrim)
 	}
+	return c
 }
 
-// caller returns a string that returns a file and line from a specified depth
-// in the callstack.
-// func caller(depth int) string {
-func caller(depth int, prefixTrim string) string {
-	c := stack.Caller(depth)
-	// The format string here has special meaning. See
-	// https://godoc.org/github.com/go-stack/stack#Call.Format
-	const format = "%+k/%s:%d"
+// funcPackage returns the package path of the fully qualified function name fn.
+func funcPackage(fn string) string {
+	// Strip everything from the first dot after the last slash of the function
+	// name to get the package path.
+	start := strings.LastIndex(fn, "/") + 1
+	if i := strings.Index(fn[start:], "."); i != -1 {
+		return fn[:start+i]
+	}
+	return fn
+}
+
+// callerFunc returns the frame's fully qualified function name.
+func callerFunc(frame runtime.Frame, prefixTrim string) string {
 	if prefixTrim != "" {
-		return strings.TrimPrefix(fmt.Sprintf(format, c, c, c), prefixTrim)
+		return strings.TrimPrefix(frame.Function, prefixTrim)
 	}
-	return fmt.Sprintf(format, c, c, c)
+	return frame.Function
+}
+
+// callerKey identifies a set of caller attributes in the callerCache.
+type callerKey struct {
+	pc         uintptr
+	format     CallerFormat
+	key        string
+	prefixTrim string
+}
+
+// callerCache holds the caller attributes for each call site so that they only
+// need to be formatted once.
+var callerCache = struct {
+	sync.RWMutex
+	m map[callerKey][]slog.Attr
+}{m: make(map[callerKey][]slog.Attr)}
+
+// callerAttrs returns the attributes describing the caller at pc.
+func (l *L) callerAttrs(pc uintptr) []slog.Attr {
+	key := callerKey{pc: pc, format: l.callerFormat, key: l.keys.Caller, prefixTrim: l.callerPrefixTrim}
+
+	callerCache.RLock()
+	attrs, ok := callerCache.m[key]
+	callerCache.RUnlock()
+	if ok {
+		return attrs
+	}
+
+	frame := callerFrame(pc)
+	switch l.callerFormat {
+	case CallerFunc:
+		attrs = []slog.Attr{slog.String(l.keys.Caller, callerFunc(frame, l.callerPrefixTrim))}
+	case CallerFileAndFunc:
+		attrs = []slog.Attr{
+			slog.String(l.keys.Caller+"_file", callerFile(frame, l.callerPrefixTrim)),
+			slog.String(l.keys.Caller+"_func", callerFunc(frame, l.callerPrefixTrim)),
+		}
+	default:
+		attrs = []slog.Attr{slog.String(l.keys.Caller, callerFile(frame, l.callerPrefixTrim))}
+	}
+
+	callerCache.Lock()
+	callerCache.m[key] = attrs
+	callerCache.Unlock()
+
+	return attrs
 }
 
 // New returns a sub-logger with the name appended to the existing logger's source
 func (l *L) New(name string) *L {
-	return &L{
-		src:              append(l.src, name),
-		slogger:          l.slogger.With(slog.String("src", strings.Join(append(l.src, name), "."))),
-		showCaller:       l.showCaller,
-		callerPrefixTrim: l.callerPrefixTrim,
+	if l == nil {
+		return nil
+	}
+
+	src := append(l.src[:len(l.src):len(l.src)], name)
+	var attrs []slog.Attr
+	if l.keys.Source != KeyOmit {
+		attrs = append(attrs, slog.String(l.keys.Source, strings.Join(src, ".")))
+	}
+	p := l
+	if l.srcPathKey != "" {
+		// Replace the parent's path rather than repeating the key.
+		p = l.Without(l.srcPathKey)
+		attrs = append(attrs, slog.Any(l.srcPathKey, src))
+	}
+	var c *L
+	if len(attrs) == 0 {
+		c = p.clone()
+	} else {
+		c = p.withAttrs(attrs...)
+	}
+	c.src = src
+	c.muted = l.srcFilter.muted(src)
+	if l.registry != nil {
+		c.level = l.registry.levelVar(strings.Join(src[1:], "."))
 	}
+	return c
 }
 
 // With returns a logger with the keyvals appended to the existing logger
 func (l *L) With(keyvals ...any) *L {
-	return &L{
-		src:              l.src,
-		slogger:          l.slogger.With(keyvals...),
-		showCaller:       l.showCaller,
-		callerPrefixTrim: l.callerPrefixTrim,
+	if l == nil {
+		return nil
+	}
+
+	var malformed slog.Attr
+	if l.strictKeys {
+		keyvals, malformed = l.checkKeyvals(keyvals, callerPC(2+l.callerSkip))
 	}
+	attrs := toAttrs(keyvals)
+	if malformed.Key != "" {
+		attrs = append(attrs, malformed)
+	}
+	return l.withAttrs(attrs...)
+}
+
+// Without returns a logger with all attributes previously added with the given
+// keys removed, for example to strip a request ID before handing a logger off to
+// a background job.
+func (l *L) Without(keys ...string) *L {
+	if l == nil {
+		return nil
+	}
+
+	c := l.clone()
+	c.attrs = nil
+	for _, a := range l.attrs {
+		if !slices.Contains(keys, a.Key) {
+			c.attrs = append(c.attrs, a)
+		}
+	}
+	c.slogger = withAttrs(l.base, c.attrs)
+	c.audit = withAttrs(l.auditBase, c.attrs)
+	return c
+}
+
+// withAttrs returns a copy of the logger with attrs appended.
+func (l *L) withAttrs(attrs ...slog.Attr) *L {
+	c := l.clone()
+	c.attrs = append(c.attrs, attrs...)
+	if l.dedupeAttrs {
+		c.attrs = dedupeAttrs(c.attrs)
+		c.slogger = withAttrs(l.base, c.attrs)
+		c.audit = withAttrs(l.auditBase, c.attrs)
+		return c
+	}
+	c.slogger = withAttrs(l.slogger, attrs)
+	c.audit = withAttrs(l.audit, attrs)
+	return c
+}
+
+// withAttrs returns a logger whose handler includes attrs.
+func withAttrs(lg *slog.Logger, attrs []slog.Attr) *slog.Logger {
+	if len(attrs) == 0 {
+		return lg
+	}
+	return slog.New(lg.Handler().WithAttrs(attrs))
+}
+
+// badKey is the key slog uses for values without a key.
+const badKey = "!BADKEY"
+
+// toAttrs converts alternating keys and values to attributes the same way
+// slog.Logger.With does.
+func toAttrs(keyvals []any) []slog.Attr {
+	var n int
+	for i := 0; i < len(keyvals); i++ {
+		if _, ok := keyvals[i].(string); ok {
+			i++
+		}
+		n++
+	}
+
+	attrs := make([]slog.Attr, 0, n)
+	for len(keyvals) > 0 {
+		var a slog.Attr
+		switch k := keyvals[0].(type) {
+		case string:
+			if len(keyvals) == 1 {
+				a = slog.String(badKey, k)
+				keyvals = nil
+			} else {
+				a = slog.Any(k, keyvals[1])
+				keyvals = keyvals[2:]
+			}
+		case slog.Attr:
+			a = k
+			keyvals = keyvals[1:]
+		default:
+			a = slog.Any(badKey, k)
+			keyvals = keyvals[1:]
+		}
+		if a.Value.Kind() == slog.KindGroup && len(a.Value.Group()) == 0 {
+			continue
+		}
+		attrs = append(attrs, a)
+	}
+	return attrs
+}
+
+// onceKeys holds the keys passed to Once that have already been logged.
+var onceKeys sync.Map
+
+// Once returns a logger that only logs the first message for the given key per
+// process, no matter how many times or from which logger Once is called with it.
+// This is useful for deprecation warnings and configuration fallbacks that would
+// otherwise be logged on every request:
+//
+//	l.Once("legacy-config").Warn("legacy config format is deprecated")
+func (l *L) Once(key string) *L {
+	if l == nil {
+		return nil
+	}
+
+	c := l.clone()
+	c.onceKey = key
+	return c
+}
+
+// AddCallerSkip returns a logger that skips n additional stack frames when
+// determining the caller. This is useful for packages that wrap *L in their own
+// helpers so that the reported caller is the helper's caller.
+func (l *L) AddCallerSkip(n int) *L {
+	if l == nil {
+		return nil
+	}
+
+	c := l.clone()
+	c.callerSkip += n
+	return c
+}
+
+// clone returns a shallow copy of the logger. The src and attrs slices are capped
+// so that appending to them in a sub-logger never modifies the parent's backing
+// array.
+func (l *L) clone() *L {
+	c := *l
+	c.src = c.src[:len(c.src):len(c.src)]
+	c.attrs = c.attrs[:len(c.attrs):len(c.attrs)]
+	c.to = c.to[:len(c.to):len(c.to)]
+	return &c
 }
 
 // Debug logs a message at the debug level
@@ -178,23 +850,159 @@ func (l *L) Err(msg any, keyvals ...any) {
 	l.log(context.Background(), slog.LevelError, msg, keyvals...)
 }
 
+// DebugCtx logs a message at the debug level, including any attributes returned
+// by the logger's context extractors for ctx
+func (l *L) DebugCtx(ctx context.Context, msg any, keyvals ...any) {
+	l.log(ctx, slog.LevelDebug, msg, keyvals...)
+}
+
+// InfoCtx logs a message at the info level, including any attributes returned
+// by the logger's context extractors for ctx
+func (l *L) InfoCtx(ctx context.Context, msg any, keyvals ...any) {
+	l.log(ctx, slog.LevelInfo, msg, keyvals...)
+}
+
+// WarnCtx logs a message at the warning level, including any attributes
+// returned by the logger's context extractors for ctx
+func (l *L) WarnCtx(ctx context.Context, msg any, keyvals ...any) {
+	l.log(ctx, slog.LevelWarn, msg, keyvals...)
+}
+
+// ErrCtx logs a message at the error level, including any attributes returned
+// by the logger's context extractors for ctx
+func (l *L) ErrCtx(ctx context.Context, msg any, keyvals ...any) {
+	l.log(ctx, slog.LevelError, msg, keyvals...)
+}
+
+// Log logs a message at level, such as one named with RegisterLevel, including
+// any attributes returned by the logger's context extractors for ctx
+func (l *L) Log(ctx context.Context, level slog.Level, msg any, keyvals ...any) {
+	l.log(ctx, level, msg, keyvals...)
+}
+
 // Fatal logs a message at the fatal level and also exits the program by calling
-// os.Exit
+// os.Exit. If the logger was created with WithGoroutineDump, the stacks of all
+// goroutines are dumped first.
 func (l *L) Fatal(msg any, keyvals ...any) {
 	l.log(context.Background(), LevelFatal, msg, keyvals...)
-	os.Exit(1)
+	l.exit()
+}
+
+// FatalIfError logs err as LogError does, but at the fatal level, and exits
+// the program as Fatal does if err is non-nil. It is meant for startup code
+// paths, such as loading configuration, where any error is unrecoverable.
+func (l *L) FatalIfError(err error, msg string, keyvals ...any) {
+	if err == nil {
+		return
+	}
+	if l.enabled(context.Background(), LevelFatal) {
+		l.log(context.Background(), LevelFatal, msg, l.errorKeyvals(err, keyvals)...)
+	}
+	l.exit()
+}
+
+// osExit is replaced in tests.
+var osExit = os.Exit
+
+// exit dumps goroutines if configured to, closes the logger and exits the
+// program.
+func (l *L) exit() {
+	if l != nil && l.dumpOnFatal {
+		l.DumpGoroutines()
+	}
+	_ = l.Close()
+	osExit(1)
+}
+
+// Must returns v if err is nil. Otherwise it logs err to the Default logger at
+// the fatal level and exits the program.
+//
+//	cfg := logger.Must(config.Load(path))
+func Must[T any](v T, err error) T {
+	if err != nil {
+		Default().AddCallerSkip(1).FatalIfError(err, "unrecoverable error")
+	}
+	return v
 }
 
 func (l *L) log(ctx context.Context, lvl slog.Level, msg any, keyvals ...any) {
-	if l == nil {
+	forced := l.traceForced(ctx)
+	if !forced && !l.enabled(ctx, lvl) {
 		return
 	}
 
-	if l.showCaller {
-		keyvals = append(keyvals, slog.String("caller", caller(3, l.callerPrefixTrim)))
+	if l.onceKey != "" {
+		if _, logged := onceKeys.LoadOrStore(l.onceKey, struct{}{}); logged {
+			return
+		}
 	}
 
-	l.slogger.Log(ctx, lvl, toString(msg), keyvals...)
+	if !forced && l.sampler != nil && !l.sampler.sample(lvl) {
+		l.stats.drop(1)
+		return
+	}
+
+	now := l.now()
+	m := toString(msg)
+	if !forced && l.adaptive != nil && !l.adaptiveSample(ctx, now, lvl, m) {
+		l.stats.drop(1)
+		return
+	}
+
+	r := slog.NewRecord(now, lvl, m, 0)
+	for _, extract := range l.extractors {
+		r.AddAttrs(extract(ctx)...)
+	}
+	var malformed slog.Attr
+	if l.strictKeys {
+		keyvals, malformed = l.checkKeyvals(keyvals, callerPC(3+l.callerSkip))
+	}
+	r.Add(keyvals...)
+	if len(l.filters) > 0 && l.filtered(ctx, r) {
+		l.stats.drop(1)
+		return
+	}
+	if malformed.Key != "" {
+		r.AddAttrs(malformed)
+	}
+	if l.errorCodes && lvl >= slog.LevelError {
+		r.AddAttrs(l.checkErrorCode(r)...)
+	}
+	if l.showCaller && lvl >= l.callerMinLevel {
+		r.AddAttrs(l.callerAttrs(callerPC(3 + l.callerSkip))...)
+	}
+	if l.numericLevel != NumericLevelNone && l.numericLevelKey != "" {
+		r.AddAttrs(slog.Int64(l.numericLevelKey, l.numericLevel.value(lvl)))
+	}
+	if l.seq != nil {
+		r.AddAttrs(slog.Uint64(l.seqKey, l.seq.Add(1)))
+	}
+	if l.recordIDKey != "" {
+		r.AddAttrs(slog.String(l.recordIDKey, newULID(r.Time)))
+	}
+
+	h := l.slogger.Handler()
+	if l.dedupeAttrs {
+		r, h = l.dedupeRecord(r)
+	}
+	l.sendToSinks(r)
+	l.sendToNamedSinks(ctx, r)
+	_ = h.Handle(ctx, r)
+}
+
+// enabled reports whether a message at the given level would be logged, so that
+// callers can return before doing any work to build the message.
+func (l *L) enabled(ctx context.Context, lvl slog.Level) bool {
+	if l == nil || l.muted {
+		return false
+	}
+	if l.debugForced(ctx, lvl) {
+		return true
+	}
+	if l.level != nil && lvl < l.level.Level() {
+		return false
+	}
+	return l.slogger.Enabled(ctx, lvl)
 }
 
 func toString(s any) string {
@@ -210,6 +1018,23 @@ func toString(s any) string {
 	}
 }
 
+// Close flushes and finalizes any buffered output, such as a compressed stream.
+// The destination itself is not closed. Loggers derived from the same root share
+// their output, so closing any of them closes it for all.
+func (l *L) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	// Closers wrap the ones added before them, so they are closed in reverse to
+	// flush each one into the next.
+	var errs []error
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		errs = append(errs, l.closers[i].Close())
+	}
+	return errors.Join(errs...)
+}
+
 // Default returns a default logger implementation
 func Default() *L {
 	return New(
@@ -218,25 +1043,62 @@ func Default() *L {
 	)
 }
 
-// Silence returns a logger that writes everything to /dev/null. Useful for
-// silencing log output from tests
+// Nop returns a logger that discards everything logged to it without doing any
+// work. It is a nil *L.
+func Nop() *L {
+	return nil
+}
+
+// Silence returns a logger that discards everything. Useful for silencing log
+// output from tests. Unlike Nop, it is a regular logger that can be derived
+// from, but none of the levels are enabled, so records are never formatted.
 func Silence() *L {
-	return New(
+	l := New(
 		WithDestination(io.Discard),
 		WithName("discard"),
 	)
+	l.base = slog.New(discardHandler{})
+	l.auditBase = l.base
+	l.slogger = withAttrs(l.base, l.attrs)
+	l.audit = withAttrs(l.auditBase, l.attrs)
+	return l
 }
 
+// discardHandler is a slog.Handler that discards everything.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
 type multiError interface {
 	WrappedErrors() []error
 }
 
 // LogError logs an error. It automatically unwinds multi-errors (not recursively...yet).
 func (l *L) LogError(msg string, err error, keyvals ...any) {
+	if !l.enabled(context.Background(), slog.LevelError) {
+		return
+	}
+	l.log(context.Background(), slog.LevelError, msg, l.errorKeyvals(err, keyvals)...)
+}
+
+// errorKeyvals appends the attributes LogError logs for err to keyvals.
+func (l *L) errorKeyvals(err error, keyvals []any) []any {
+	if l.errorCodes && !hasKey(keyvals, errorCodeKey) {
+		if code := errorCode(err); code != "" {
+			keyvals = append(keyvals, slog.String(errorCodeKey, code))
+		}
+	}
+
+	if l.errorVerbose && err != nil {
+		keyvals = append(keyvals, errorDetails(err)...)
+	}
+
 	mErr, ok := err.(multiError)
 	if !ok {
-		l.log(context.Background(), slog.LevelError, msg, append(keyvals, slog.String("error", err.Error()))...)
-		return
+		return append(keyvals, slog.String("error", err.Error()))
 	}
 
 	errs := mErr.WrappedErrors()
@@ -251,5 +1113,5 @@ func (l *L) LogError(msg string, err error, keyvals ...any) {
 		)
 	}
 
-	l.log(context.Background(), slog.LevelError, msg, keyvals...)
+	return keyvals
 }
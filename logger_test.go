@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -67,6 +70,23 @@ func TestLogger(t *testing.T) {
 		require.Contains(t, buf.String(), "src=somelogger.sublogger2")
 	})
 
+	t.Run("without", func(t *testing.T) {
+		defer buf.Reset()
+
+		sub := l.New("sublogger3").With("request_id", "abc123", "key6", "value6")
+		sub.Without("request_id", "key1").Info("background")
+
+		require.NotContains(t, buf.String(), "request_id")
+		require.NotContains(t, buf.String(), "key1")
+		require.Contains(t, buf.String(), "key6=value6")
+		require.Contains(t, buf.String(), "src=somelogger.sublogger3")
+		buf.Reset()
+
+		sub.Info("request")
+		require.Contains(t, buf.String(), "request_id=abc123")
+		require.Contains(t, buf.String(), "key1=value1")
+	})
+
 	t.Run("LogError", func(t *testing.T) {
 		t.Run("single", func(t *testing.T) {
 			defer buf.Reset()
@@ -121,6 +141,18 @@ func TestLogger(t *testing.T) {
 		})
 	})
 
+	t.Run("disabled", func(t *testing.T) {
+		defer buf.Reset()
+
+		l := New(WithDestination(&buf), WithLevel("fatal"))
+		err := &countingError{}
+		l.LogError("some error", err)
+		l.LogError("some error", &myMulti{errs: []error{err}})
+
+		require.Empty(t, buf.String())
+		require.Zero(t, err.calls)
+	})
+
 	t.Run("caller-trim", func(t *testing.T) {
 		defer buf.Reset()
 
@@ -146,6 +178,109 @@ func TestLogger(t *testing.T) {
 	})
 }
 
+func TestLoggerCallerSkip(t *testing.T) {
+	var buf bytes.Buffer
+
+	wrapper := func(l *L, msg string) {
+		l.Info(msg)
+	}
+
+	t.Run("option", func(t *testing.T) {
+		defer buf.Reset()
+
+		l := New(WithDestination(&buf), WithLevel("info"), WithCallerSkip(1))
+		wrapper(l, "foo")
+		_, _, line, _ := runtime.Caller(0)
+		require.Contains(t, buf.String(), fmt.Sprintf("caller=github.com/jasonhancock/go-logger/logger_test.go:%d", line-1))
+	})
+
+	t.Run("AddCallerSkip", func(t *testing.T) {
+		defer buf.Reset()
+
+		l := New(WithDestination(&buf), WithLevel("info")).AddCallerSkip(1)
+		wrapper(l.With("key1", "value1"), "foo")
+		_, _, line, _ := runtime.Caller(0)
+		require.Contains(t, buf.String(), fmt.Sprintf("caller=github.com/jasonhancock/go-logger/logger_test.go:%d", line-1))
+	})
+}
+
+func TestLoggerCallerFormat(t *testing.T) {
+	tests := []struct {
+		desc     string
+		format   CallerFormat
+		expected []string
+	}{
+		{"file", CallerFile, []string{"caller=logger_test.go:"}},
+		{"func", CallerFunc, []string{"caller=github.com/jasonhancock/go-logger.TestLoggerCallerFormat.func1\n"}},
+		{"file and func", CallerFileAndFunc, []string{
+			"caller_file=logger_test.go:",
+			"caller_func=github.com/jasonhancock/go-logger.TestLoggerCallerFormat.func1\n",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := New(
+				WithDestination(&buf),
+				WithLevel("info"),
+				WithCallerFormat(tt.format),
+				WithCallerPrefixTrim("github.com/jasonhancock/go-logger"),
+			)
+
+			l.Info("foo")
+			for _, v := range tt.expected {
+				require.Contains(t, buf.String(), v)
+			}
+		})
+	}
+}
+
+func TestLoggerCallerMinLevel(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithCallerMinLevel("warn"),
+	)
+
+	l.Info("foo")
+	require.NotContains(t, buf.String(), "caller=")
+	buf.Reset()
+
+	l.Warn("foo")
+	require.Contains(t, buf.String(), "caller=")
+}
+
+func TestLoggerOnce(t *testing.T) {
+	// onceKeys is global, so forget the keys logged for -count > 1.
+	t.Cleanup(func() {
+		onceKeys.Range(func(k, _ any) bool {
+			onceKeys.Delete(k)
+			return true
+		})
+	})
+
+	var buf bytes.Buffer
+
+	l := New(WithDestination(&buf), WithLevel("info"))
+
+	for i := 0; i < 3; i++ {
+		l.Once(t.Name()).Warn("deprecated")
+		l.New("sub").Once(t.Name()).Warn("deprecated")
+	}
+	require.Equal(t, 1, strings.Count(buf.String(), "msg=deprecated"))
+
+	t.Run("disabled level does not consume key", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.Once(t.Name()).Debug("fallback")
+		l.Once(t.Name()).Info("fallback")
+		require.Equal(t, 1, strings.Count(buf.String(), "msg=fallback"))
+	})
+}
+
 func TestLoggerJSON(t *testing.T) {
 	var buf bytes.Buffer
 
@@ -169,6 +304,176 @@ func TestLoggerJSON(t *testing.T) {
 	require.Equal(t, "foo", data["msg"])
 }
 
+func TestLoggerClock(t *testing.T) {
+	var buf bytes.Buffer
+	ts := time.Date(2023, 4, 13, 17, 38, 13, 516398000, time.UTC)
+
+	l := New(
+		WithDestination(&buf),
+		WithName("somelogger"),
+		WithLevel("info"),
+		WithTimeLocation(time.UTC),
+		WithClock(func() time.Time { return ts }),
+	)
+
+	l.Info("foo")
+	require.Contains(t, buf.String(), "ts=2023-04-13T17:38:13.516398Z ")
+}
+
+func TestLoggerTimeFormat(t *testing.T) {
+	ts := time.Date(2023, 4, 13, 17, 38, 13, 516398000, time.UTC)
+
+	tests := []struct {
+		desc     string
+		layout   string
+		expected string
+	}{
+		{"default", "", "ts=2023-04-13T17:38:13.516398Z "},
+		{"rfc3339 millis", TimeFormatRFC3339Milli, "ts=2023-04-13T17:38:13.516Z "},
+		{"unix", TimeFormatUnix, "ts=1681407493 "},
+		{"unix millis", TimeFormatUnixMilli, "ts=1681407493516 "},
+		{"custom", time.Kitchen, "ts=5:38PM "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := New(
+				WithDestination(&buf),
+				WithLevel("info"),
+				WithTimeLocation(time.UTC),
+				WithTimeFormat(tt.layout),
+				WithClock(func() time.Time { return ts }),
+			)
+
+			l.Info("foo")
+			require.Contains(t, buf.String(), tt.expected)
+		})
+	}
+
+	t.Run("json number", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithFormat(FormatJSON),
+			WithTimeFormat(TimeFormatUnixMilli),
+			WithClock(func() time.Time { return ts }),
+		)
+
+		l.Info("foo")
+		require.Contains(t, buf.String(), `"ts":1681407493516,`)
+	})
+}
+
+func TestLoggerNumericLevel(t *testing.T) {
+	tests := []struct {
+		desc     string
+		numeric  NumericLevel
+		key      string
+		expected []string
+	}{
+		{"slog replaces label", NumericLevelSlog, "", []string{"level=4 "}},
+		{"syslog replaces label", NumericLevelSyslog, "", []string{"level=4 "}},
+		{"syslog additional key", NumericLevelSyslog, "severity", []string{"level=warn ", "severity=4"}},
+		{"none", NumericLevelNone, "severity", []string{"level=warn "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := New(
+				WithDestination(&buf),
+				WithLevel("info"),
+				WithNumericLevel(tt.numeric, tt.key),
+			)
+
+			l.Warn("foo")
+			for _, v := range tt.expected {
+				require.Contains(t, buf.String(), v)
+			}
+		})
+	}
+}
+
+func TestNumericLevelValue(t *testing.T) {
+	tests := []struct {
+		level  slog.Level
+		slog   int64
+		syslog int64
+	}{
+		{LevelFatal, 12, 2},
+		{slog.LevelError, 8, 3},
+		{slog.LevelWarn, 4, 4},
+		{slog.LevelInfo + 2, 2, 5},
+		{slog.LevelInfo, 0, 6},
+		{slog.LevelDebug, -4, 7},
+		{LevelAll, -10, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			require.Equal(t, tt.slog, NumericLevelSlog.value(tt.level))
+			require.Equal(t, tt.syslog, NumericLevelSyslog.value(tt.level))
+		})
+	}
+}
+
+func TestLoggerReplaceAttr(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithReplaceAttr(func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case "level":
+				a.Value = slog.StringValue(strings.ToUpper(a.Value.String()))
+			case "secret":
+				return slog.Attr{}
+			case "elapsed":
+				a.Value = slog.StringValue(a.Value.Duration().String())
+			}
+			return a
+		}),
+	)
+
+	l.Info("foo", "secret", "hunter2", "elapsed", 1500*time.Millisecond)
+	require.Contains(t, buf.String(), "level=INFO ")
+	require.Contains(t, buf.String(), "elapsed=1.5s")
+	require.NotContains(t, buf.String(), "secret")
+}
+
+func TestLoggerKeyNames(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithName("somelogger"),
+		WithLevel("info"),
+		WithFormat(FormatJSON),
+		WithKeyNames(KeyNames{
+			Time:    "@timestamp",
+			Message: "message",
+			Level:   "severity",
+			Caller:  KeyOmit,
+		}),
+	)
+
+	l.New("sub").Info("foo", "key1", "value1")
+	var data map[string]string
+	require.NoError(t, json.NewDecoder(&buf).Decode(&data))
+	require.Equal(t, "foo", data["message"])
+	require.Equal(t, "info", data["severity"])
+	require.Equal(t, "somelogger.sub", data["src"])
+	require.Equal(t, "value1", data["key1"])
+	require.Contains(t, data["@timestamp"], fmt.Sprintf("%d", time.Now().Year()))
+	require.NotContains(t, data, "caller")
+	require.NotContains(t, data, "msg")
+	require.NotContains(t, data, "level")
+	require.NotContains(t, data, "ts")
+}
+
 func TestToString(t *testing.T) {
 	tests := []struct {
 		desc     string
@@ -193,6 +498,15 @@ type myError struct{}
 
 func (e *myError) Error() string { return "my error" }
 
+type countingError struct {
+	calls int
+}
+
+func (e *countingError) Error() string {
+	e.calls++
+	return "counting error"
+}
+
 type myStringer struct{}
 
 func (s *myStringer) String() string { return "my string" }
@@ -210,3 +524,121 @@ func (m *myMulti) WrappedErrors() []error {
 func (m *myMulti) Error() string {
 	return errors.Join(m.errs...).Error()
 }
+
+func TestLoggerBuiltinKeysFromCaller(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithCaller(false),
+		WithTimeFormat(TimeFormatUnix),
+		WithClock(func() time.Time { return time.Unix(1, 0) }),
+	)
+
+	l.Info("foo", "level", "custom", "time", "noon")
+	require.Equal(t, "ts=1 level=info msg=foo src=go-logger.test level=custom time=noon\n", buf.String())
+}
+
+func TestSilence(t *testing.T) {
+	l := Silence()
+	sub := l.New("sub").With("key1", "value1")
+	require.NoError(t, sub.Audit("login", "alice", "app"))
+
+	err := errors.New("some error")
+	allocs := testing.AllocsPerRun(100, func() {
+		sub.Info("some message", "key2", "value2")
+		sub.LogError("some message", err)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestToAttrs(t *testing.T) {
+	keyvals := []any{"a", 1, slog.Int("b", 2), 3, slog.Group("empty"), "c"}
+
+	var r slog.Record
+	r.Add(keyvals...)
+	var want []slog.Attr
+	r.Attrs(func(a slog.Attr) bool {
+		want = append(want, a)
+		return true
+	})
+
+	require.Equal(t, want, toAttrs(keyvals))
+}
+
+func TestLoggerSequence(t *testing.T) {
+	t.Run("logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithCaller(false),
+			WithKeyNames(KeyNames{Time: KeyOmit}),
+			WithSequence(""),
+			WithFilter(Not(MessageContains("skip"))),
+		)
+
+		l.Info("one")
+		l.New("sub").Info("two")
+		l.Info("skip")
+		l.With("key", "value").Info("three")
+		require.Equal(t, `level=info msg=one src=go-logger.test seq=1
+level=info msg=two src=go-logger.test src=go-logger.test.sub seq=2
+level=info msg=three src=go-logger.test key=value seq=3
+`, buf.String())
+	})
+
+	t.Run("dedup", func(t *testing.T) {
+		var buf bytes.Buffer
+		ts := time.Unix(0, 0)
+		l := New(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithCaller(false),
+			WithKeyNames(KeyNames{Time: KeyOmit}),
+			WithSequence("n"),
+			WithDeduplication(time.Minute),
+			WithClock(func() time.Time { return ts }),
+		)
+
+		l.Info("same")
+		l.Info("same")
+		l.Info("same")
+		l.Info("different")
+		require.Equal(t, `level=info msg=same src=go-logger.test n=1
+level=info msg=same src=go-logger.test n=3 repeated=2
+level=info msg=different src=go-logger.test n=4
+`, buf.String())
+	})
+
+	t.Run("process", func(t *testing.T) {
+		var buf bytes.Buffer
+		opts := []Option{WithDestination(&buf), WithLevel("info"), WithProcessSequence("")}
+		l1, l2 := New(opts...), New(opts...)
+
+		start := processSeq.Load()
+		l1.Info("one")
+		l2.Info("two")
+		require.Contains(t, buf.String(), fmt.Sprintf("seq=%d", start+1))
+		require.Contains(t, buf.String(), fmt.Sprintf("seq=%d", start+2))
+	})
+}
+
+func TestLoggerSourcePath(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithFormat(FormatJSON),
+		WithLevel("info"),
+		WithCaller(false),
+		WithName("api"),
+		WithKeyNames(KeyNames{Time: KeyOmit, Source: KeyOmit}),
+		WithSourcePath(""),
+	)
+
+	l.Info("root")
+	l.New("db").With("user", "bob").New("tx").Info("nested")
+	require.Equal(t, `{"level":"info","msg":"root","src_path":["api"]}
+{"level":"info","msg":"nested","user":"bob","src_path":["api","db","tx"]}
+`, buf.String())
+}
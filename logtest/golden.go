@@ -0,0 +1,80 @@
+// Package logtest provides helpers for testing log output produced by
+// github.com/jasonhancock/go-logger.
+package logtest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jasonhancock/go-logger"
+)
+
+// GoldenTime is the timestamp attached to every record logged via Golden.
+var GoldenTime = time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+
+var callerLine = regexp.MustCompile(`(caller[="':]+[^\s"']+):\d+`)
+
+// Golden invokes fn with a logger writing to an in-memory buffer and compares
+// the output to the contents of goldenPath. Timestamps are frozen to GoldenTime
+// and line numbers in caller values are replaced with "LINE" so that the golden
+// file only changes when the log format does. When LOGTEST_UPDATE is set to a
+// true value, or the test binary defines its own -update flag and is run with
+// it, goldenPath is rewritten with the current output instead. The logger logs
+// at all levels unless opts specify otherwise.
+func Golden(t testing.TB, fn func(l *logger.L), goldenPath string, opts ...logger.Option) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	opts = append([]logger.Option{logger.WithLevel("all")}, opts...)
+	fn(logger.New(append(opts,
+		logger.WithDestination(&buf),
+		logger.WithClock(func() time.Time { return GoldenTime }),
+		logger.WithTimeLocation(time.UTC),
+	)...))
+
+	got := Normalize(buf.Bytes())
+
+	if updating(flag.CommandLine) {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Fatalf("creating golden file directory: %s", err)
+		}
+		if err := os.WriteFile(goldenPath, got, 0o644); err != nil {
+			t.Fatalf("updating golden file: %s", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("reading golden file (set LOGTEST_UPDATE=1 to create it): %s", err)
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Errorf("log output does not match %s (set LOGTEST_UPDATE=1 to accept)\n--- want:\n%s\n--- got:\n%s", goldenPath, want, got)
+	}
+}
+
+// updating reports whether golden files should be rewritten. The package doesn't
+// register a flag of its own so that it can't clash with the test binary's.
+func updating(fs *flag.FlagSet) bool {
+	if f := fs.Lookup("update"); f != nil {
+		if g, ok := f.Value.(flag.Getter); ok {
+			if b, ok := g.Get().(bool); ok && b {
+				return true
+			}
+		}
+	}
+	b, _ := strconv.ParseBool(os.Getenv("LOGTEST_UPDATE"))
+	return b
+}
+
+// Normalize replaces line numbers in caller values with "LINE".
+func Normalize(b []byte) []byte {
+	return callerLine.ReplaceAll(b, []byte("${1}:LINE"))
+}
@@ -0,0 +1,66 @@
+package logtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jasonhancock/go-logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGolden(t *testing.T) {
+	fn := func(l *logger.L) {
+		l.Info("some message", "key1", "value1")
+		l.New("sub").Warn("another message")
+	}
+
+	t.Run("logfmt", func(t *testing.T) {
+		Golden(t, fn, "testdata/logfmt.golden", logger.WithName("golden"))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		Golden(t, fn, "testdata/json.golden", logger.WithName("golden"), logger.WithFormat(logger.FormatJSON))
+	})
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "update.golden")
+	fn := func(l *logger.L) { l.Info("some message") }
+
+	t.Setenv("LOGTEST_UPDATE", "1")
+	Golden(t, fn, path)
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(b), "msg=\"some message\"")
+
+	t.Setenv("LOGTEST_UPDATE", "")
+	require.False(t, updating(flag.CommandLine))
+	require.Nil(t, flag.Lookup("update"))
+
+	// The test binary's own -update flag is honored.
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("update", false, "")
+	require.False(t, updating(fs))
+	require.NoError(t, fs.Parse([]string{"-update"}))
+	require.True(t, updating(fs))
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		desc     string
+		input    string
+		expected string
+	}{
+		{"logfmt", "caller=foo/bar.go:12 msg=x", "caller=foo/bar.go:LINE msg=x"},
+		{"json", `{"caller":"foo/bar.go:12","msg":"x"}`, `{"caller":"foo/bar.go:LINE","msg":"x"}`},
+		{"no caller", "msg=x:12", "msg=x:12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			require.Equal(t, tt.expected, string(Normalize([]byte(tt.input))))
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package logtest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// DecodeMsgpack decodes the records written by a logger using
+// logger.FormatMsgpack. Integers are decoded as int64 or uint64, times as
+// time.Time in UTC, and groups as nested maps.
+func DecodeMsgpack(r io.Reader) ([]map[string]any, error) {
+	br := bufio.NewReader(r)
+
+	var records []map[string]any
+	for {
+		if _, err := br.Peek(1); errors.Is(err, io.EOF) {
+			return records, nil
+		}
+
+		v, err := decodeMsgpack(br)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected record to be a map, got %T", v)
+		}
+		records = append(records, m)
+	}
+}
+
+func decodeMsgpack(r *bufio.Reader) (any, error) {
+	t, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case t <= 0x7f:
+		return int64(t), nil
+	case t >= 0xe0:
+		return int64(int8(t)), nil
+	case t&0xf0 == 0x80:
+		return decodeMsgpackMap(r, int(t&0x0f))
+	case t&0xf0 == 0x90:
+		return decodeMsgpackArray(r, int(t&0x0f))
+	case t&0xe0 == 0xa0:
+		return decodeMsgpackString(r, int(t&0x1f))
+	}
+
+	switch t {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := readMsgpackLen(r, t-0xc4)
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackBytes(r, n)
+	case 0xc7:
+		return decodeMsgpackTime(r)
+	case 0xcb:
+		b, err := readMsgpackBytes(r, 8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		b, err := readMsgpackBytes(r, 1<<(t-0xcc))
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackUint(b), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		b, err := readMsgpackBytes(r, 1<<(t-0xd0))
+		if err != nil {
+			return nil, err
+		}
+		u := readMsgpackUint(b)
+		shift := 64 - 8*len(b)
+		return int64(u<<shift) >> shift, nil
+	case 0xd9, 0xda, 0xdb:
+		n, err := readMsgpackLen(r, t-0xd9)
+		if err != nil {
+			return nil, err
+		}
+		return decodeMsgpackString(r, n)
+	case 0xdc, 0xdd:
+		n, err := readMsgpackLen(r, t-0xdc+1)
+		if err != nil {
+			return nil, err
+		}
+		return decodeMsgpackArray(r, n)
+	case 0xde, 0xdf:
+		n, err := readMsgpackLen(r, t-0xde+1)
+		if err != nil {
+			return nil, err
+		}
+		return decodeMsgpackMap(r, n)
+	}
+
+	return nil, fmt.Errorf("unsupported msgpack type 0x%02x", t)
+}
+
+// readMsgpackLen reads a big endian length of 1, 2, or 4 bytes for size 0, 1, or
+// 2 respectively.
+func readMsgpackLen(r *bufio.Reader, size byte) (int, error) {
+	b, err := readMsgpackBytes(r, 1<<size)
+	if err != nil {
+		return 0, err
+	}
+	return int(readMsgpackUint(b)), nil
+}
+
+func readMsgpackUint(b []byte) uint64 {
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u
+}
+
+func readMsgpackBytes(r *bufio.Reader, n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func decodeMsgpackString(r *bufio.Reader, n int) (string, error) {
+	b, err := readMsgpackBytes(r, n)
+	return string(b), err
+}
+
+func decodeMsgpackArray(r *bufio.Reader, n int) ([]any, error) {
+	a := make([]any, n)
+	for i := range a {
+		v, err := decodeMsgpack(r)
+		if err != nil {
+			return nil, err
+		}
+		a[i] = v
+	}
+	return a, nil
+}
+
+func decodeMsgpackMap(r *bufio.Reader, n int) (map[string]any, error) {
+	m := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		k, err := decodeMsgpack(r)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected map key to be a string, got %T", k)
+		}
+
+		v, err := decodeMsgpack(r)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// decodeMsgpackTime decodes the 96-bit form of the timestamp extension type.
+func decodeMsgpackTime(r *bufio.Reader) (time.Time, error) {
+	b, err := readMsgpackBytes(r, 14)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if b[0] != 12 || int8(b[1]) != -1 {
+		return time.Time{}, fmt.Errorf("unsupported msgpack extension type %d", int8(b[1]))
+	}
+
+	nsec := binary.BigEndian.Uint32(b[2:6])
+	sec := int64(binary.BigEndian.Uint64(b[6:14]))
+	return time.Unix(sec, int64(nsec)).UTC(), nil
+}
@@ -0,0 +1,76 @@
+package logtest
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jasonhancock/go-logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeMsgpack(t *testing.T) {
+	var buf bytes.Buffer
+	ts := time.Date(2023, 4, 13, 17, 38, 13, 516398000, time.UTC)
+
+	l := logger.New(
+		logger.WithDestination(&buf),
+		logger.WithName("app"),
+		logger.WithLevel("info"),
+		logger.WithFormat(logger.FormatMsgpack),
+		logger.WithClock(func() time.Time { return ts }),
+		logger.WithCaller(false),
+		logger.With("key1", "value1"),
+	)
+
+	l.New("sub").Info("foo",
+		"small", 5,
+		"negative", -100,
+		"big", int64(math.MaxInt64),
+		"min", int64(math.MinInt64),
+		"uint", uint64(math.MaxUint64),
+		"float", 1.5,
+		"bool", true,
+		"duration", 1500*time.Millisecond,
+		"err", errors.New("boom"),
+		"long", strings.Repeat("x", 300),
+		"bytes", []byte("raw"),
+		"list", []string{"a", "b"},
+		"nil", nil,
+		slog.Group("grp", slog.Int("inner", 1), slog.Group("empty")),
+	)
+	l.Warn("bar")
+
+	records, err := DecodeMsgpack(&buf)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	require.Equal(t, map[string]any{
+		"ts":       ts,
+		"level":    "info",
+		"msg":      "foo",
+		"key1":     "value1",
+		"src":      "app.sub",
+		"small":    int64(5),
+		"negative": int64(-100),
+		"big":      uint64(math.MaxInt64),
+		"min":      int64(math.MinInt64),
+		"uint":     uint64(math.MaxUint64),
+		"float":    1.5,
+		"bool":     true,
+		"duration": uint64(1500 * time.Millisecond),
+		"err":      "boom",
+		"long":     strings.Repeat("x", 300),
+		"bytes":    []byte("raw"),
+		"list":     []any{"a", "b"},
+		"nil":      nil,
+		"grp":      map[string]any{"inner": int64(1)},
+	}, records[0])
+
+	require.Equal(t, "warn", records[1]["level"])
+	require.Equal(t, "bar", records[1]["msg"])
+}
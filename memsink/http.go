@@ -0,0 +1,69 @@
+package memsink
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jasonhancock/go-logger"
+)
+
+// DefaultLimit is the number of records returned by the handler when the
+// request doesn't specify n.
+const DefaultLimit = 100
+
+// Handler returns an http.Handler responding to GET requests with the most
+// recent stored records as a JSON array, oldest first. The query parameters
+// narrow the result:
+//
+//	n      the maximum number of records to return, defaulting to DefaultLimit
+//	level  only return records at or above this level, e.g. warn
+//	src    only return records from this logger or its sub-loggers, e.g. app.db
+func (s *Sink) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		q := r.URL.Query()
+		n := DefaultLimit
+		if v := q.Get("n"); v != "" {
+			var err error
+			if n, err = strconv.Atoi(v); err != nil || n < 0 {
+				http.Error(w, "invalid n "+strconv.Quote(v), http.StatusBadRequest)
+				return
+			}
+		}
+
+		recs := s.Records()
+		if v := q.Get("level"); v != "" {
+			recs = recs.ByLevel(logger.ParseLevel(v).Level())
+		}
+		if v := q.Get("src"); v != "" {
+			recs = recs.BySource(v)
+		}
+		if len(recs) > n {
+			recs = recs[len(recs)-n:]
+		}
+		if recs == nil {
+			recs = Records{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(recs)
+	})
+}
+
+// BySource returns the records logged by the logger named src, as found in the
+// src attribute, or by its sub-loggers.
+func (rs Records) BySource(src string) Records {
+	return rs.Where(func(r Record) bool { return fromSource(r, src) })
+}
+
+func fromSource(r Record, src string) bool {
+	v, ok := r.Attrs["src"].(string)
+	return ok && (v == src || strings.HasPrefix(v, src+"."))
+}
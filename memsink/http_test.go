@@ -0,0 +1,64 @@
+package memsink
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jasonhancock/go-logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinkHandler(t *testing.T) {
+	sink := New(10)
+	l := logger.New(
+		logger.WithDestination(io.Discard),
+		logger.WithName("app"),
+		logger.WithLevel("debug"),
+		logger.WithCaller(false),
+		logger.WithRecordSink(sink.Record),
+	)
+	db := l.New("db")
+
+	l.Debug("root debug")
+	db.Debug("db debug")
+	db.Warn("db warn")
+	l.New("dbx").Warn("dbx warn")
+	l.Err("root err")
+
+	get := func(query string) []string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		sink.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var recs []struct {
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &recs))
+		msgs := []string{}
+		for _, r := range recs {
+			msgs = append(msgs, r.Msg)
+		}
+		return msgs
+	}
+
+	require.Equal(t, []string{"root debug", "db debug", "db warn", "dbx warn", "root err"}, get(""))
+	require.Equal(t, []string{"dbx warn", "root err"}, get("n=2"))
+	require.Equal(t, []string{"db warn", "dbx warn", "root err"}, get("level=warn"))
+	require.Equal(t, []string{"db debug", "db warn"}, get("src=app.db"))
+	require.Equal(t, []string{"db warn"}, get("src=app.db&level=warn"))
+	require.Equal(t, []string{}, get("src=nope"))
+
+	w := httptest.NewRecorder()
+	sink.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?n=x", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	sink.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
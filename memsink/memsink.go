@@ -0,0 +1,211 @@
+// Package memsink provides an in-memory store of recent log records that can be
+// queried, for use in tests and in-process debug endpoints.
+package memsink
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jasonhancock/go-logger"
+)
+
+// Record is a stored log record. Attributes in groups are flattened into keys
+// joined with dots.
+type Record struct {
+	Time    time.Time      `json:"time"`
+	Level   slog.Level     `json:"-"`
+	Message string         `json:"msg"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// MarshalJSON renders the level by name.
+func (r Record) MarshalJSON() ([]byte, error) {
+	type record Record
+	return json.Marshal(struct {
+		record
+		Level string `json:"level"`
+	}{record(r), logger.LevelName(r.Level)})
+}
+
+// Sink stores the most recent records, up to its capacity. Feed it with
+// logger.WithRecordSink(sink.Record), or use it as the destination of a logger
+// using logger.FormatJSON.
+type Sink struct {
+	mu      sync.RWMutex
+	records []Record
+	next    int
+	full    bool
+	subs    map[chan Record]struct{}
+}
+
+// New returns a Sink holding up to capacity records.
+func New(capacity int) *Sink {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &Sink{records: make([]Record, capacity)}
+}
+
+// Record stores r. It matches the signature expected by logger.WithRecordSink.
+func (s *Sink) Record(r slog.Record) {
+	rec := Record{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   make(map[string]any, r.NumAttrs()),
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(rec.Attrs, "", a)
+		return true
+	})
+	s.add(rec)
+}
+
+func addAttr(m map[string]any, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			addAttr(m, prefix, ga)
+		}
+		return
+	}
+	if a.Key != "" {
+		m[prefix+a.Key] = v.Any()
+	}
+}
+
+// Write stores a record encoded by logger.FormatJSON with the default key names.
+func (s *Sink) Write(p []byte) (int, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(p, &fields); err != nil {
+		return 0, fmt.Errorf("decoding record: %w", err)
+	}
+
+	rec := Record{Attrs: make(map[string]any, len(fields))}
+	for k, v := range fields {
+		switch k {
+		case "ts":
+			if ts, ok := v.(string); ok {
+				rec.Time, _ = time.Parse(time.RFC3339Nano, ts)
+				continue
+			}
+		case slog.LevelKey:
+			if lvl, ok := v.(string); ok {
+				rec.Level = logger.ParseLevel(lvl).Level()
+				continue
+			}
+		case slog.MessageKey:
+			if msg, ok := v.(string); ok {
+				rec.Message = msg
+				continue
+			}
+		}
+		flatten(rec.Attrs, k, v)
+	}
+
+	s.add(rec)
+	return len(p), nil
+}
+
+func flatten(m map[string]any, key string, v any) {
+	if group, ok := v.(map[string]any); ok {
+		for k, gv := range group {
+			flatten(m, key+"."+k, gv)
+		}
+		return
+	}
+	m[key] = v
+}
+
+func (s *Sink) add(rec Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[s.next] = rec
+	s.next++
+	if s.next == len(s.records) {
+		s.next = 0
+		s.full = true
+	}
+
+	for ch := range s.subs {
+		select {
+		case ch <- rec:
+		default:
+		}
+	}
+}
+
+// Records returns the stored records, oldest first.
+func (s *Sink) Records() Records {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.full {
+		return append(Records(nil), s.records[:s.next]...)
+	}
+	out := make(Records, 0, len(s.records))
+	out = append(out, s.records[s.next:]...)
+	return append(out, s.records[:s.next]...)
+}
+
+// Reset removes all stored records.
+func (s *Sink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.records)
+	s.next = 0
+	s.full = false
+}
+
+// ByLevel returns the stored records at or above lvl.
+func (s *Sink) ByLevel(lvl slog.Level) Records { return s.Records().ByLevel(lvl) }
+
+// ByAttr returns the stored records with an attribute named key whose value
+// has the same string form as value.
+func (s *Sink) ByAttr(key string, value any) Records { return s.Records().ByAttr(key, value) }
+
+// Between returns the stored records logged at or after start and before end.
+func (s *Sink) Between(start, end time.Time) Records { return s.Records().Between(start, end) }
+
+// Records is a list of records that can be narrowed with chained queries, e.g.
+// sink.ByLevel(slog.LevelWarn).ByAttr("user_id", 42).
+type Records []Record
+
+// ByLevel returns the records at or above lvl.
+func (rs Records) ByLevel(lvl slog.Level) Records {
+	return rs.Where(func(r Record) bool { return r.Level >= lvl })
+}
+
+// ByAttr returns the records with an attribute named key whose value has the
+// same string form as value, so ByAttr("user_id", 42) matches both 42 and "42".
+func (rs Records) ByAttr(key string, value any) Records {
+	want := fmt.Sprint(value)
+	return rs.Where(func(r Record) bool {
+		v, ok := r.Attrs[key]
+		return ok && fmt.Sprint(v) == want
+	})
+}
+
+// Between returns the records logged at or after start and before end.
+func (rs Records) Between(start, end time.Time) Records {
+	return rs.Where(func(r Record) bool { return !r.Time.Before(start) && r.Time.Before(end) })
+}
+
+// Where returns the records for which fn returns true.
+func (rs Records) Where(fn func(Record) bool) Records {
+	var out Records
+	for _, r := range rs {
+		if fn(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package memsink
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jasonhancock/go-logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinkRecord(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sink := New(3)
+	l := logger.New(
+		logger.WithDestination(sink),
+		logger.WithFormat(logger.FormatJSON),
+		logger.WithLevel("debug"),
+		logger.WithCaller(false),
+		logger.WithClock(func() time.Time { return now }),
+	)
+
+	l.Debug("one")
+	now = now.Add(time.Second)
+	l.Info("two", "user_id", 42)
+	now = now.Add(time.Second)
+	l.Warn("three", "user_id", "42", slog.Group("req", "id", "abc"))
+	now = now.Add(time.Second)
+	l.Err("four", "user_id", 7)
+
+	all := sink.Records()
+	require.Len(t, all, 3)
+	require.Equal(t, "two", all[0].Message)
+	require.Equal(t, "four", all[2].Message)
+	require.Equal(t, "abc", all[1].Attrs["req.id"])
+
+	require.Len(t, sink.ByLevel(slog.LevelWarn), 2)
+	require.Len(t, sink.ByAttr("user_id", 42), 2)
+	require.Len(t, sink.ByLevel(slog.LevelWarn).ByAttr("user_id", 42), 1)
+
+	start := time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC)
+	between := sink.Between(start, start.Add(2*time.Second))
+	require.Len(t, between, 2)
+	require.Equal(t, "two", between[0].Message)
+
+	b, err := json.Marshal(all[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"time":"2024-01-01T00:00:01Z","level":"info","msg":"two","attrs":{"src":"memsink.test","user_id":42}}`, string(b))
+
+	sink.Reset()
+	require.Empty(t, sink.Records())
+}
+
+func TestSinkRecordSink(t *testing.T) {
+	sink := New(10)
+	l := logger.New(
+		logger.WithDestination(io.Discard),
+		logger.WithLevel("info"),
+		logger.WithCaller(false),
+		logger.WithRecordSink(sink.Record),
+	)
+
+	l.With("request_id", "abc").Info("foo", "n", 1, slog.Group("g", "k", "v"))
+
+	recs := sink.ByAttr("request_id", "abc")
+	require.Len(t, recs, 1)
+	require.Equal(t, slog.LevelInfo, recs[0].Level)
+	require.Equal(t, int64(1), recs[0].Attrs["n"])
+	require.Equal(t, "v", recs[0].Attrs["g.k"])
+}
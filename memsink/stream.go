@@ -0,0 +1,92 @@
+package memsink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jasonhancock/go-logger"
+)
+
+// streamBuffer is the number of records buffered for each stream. Records
+// arriving while a stream's buffer is full are dropped for that stream rather
+// than blocking the logger.
+const streamBuffer = 256
+
+// subscribe returns a channel receiving records as they're stored.
+func (s *Sink) subscribe() chan Record {
+	ch := make(chan Record, streamBuffer)
+	s.mu.Lock()
+	if s.subs == nil {
+		s.subs = make(map[chan Record]struct{})
+	}
+	s.subs[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *Sink) unsubscribe(ch chan Record) {
+	s.mu.Lock()
+	delete(s.subs, ch)
+	s.mu.Unlock()
+}
+
+// StreamHandler returns an http.Handler streaming records to the client as
+// Server-Sent Events as they're stored, one JSON encoded record per event, until
+// the client disconnects. The level and src query parameters filter the stream
+// like they do for Handler. Records are dropped for clients that can't keep up.
+func (s *Sink) StreamHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		q := r.URL.Query()
+		filters := make([]func(Record) bool, 0, 2)
+		if v := q.Get("level"); v != "" {
+			lvl := logger.ParseLevel(v).Level()
+			filters = append(filters, func(rec Record) bool { return rec.Level >= lvl })
+		}
+		if v := q.Get("src"); v != "" {
+			filters = append(filters, func(rec Record) bool { return fromSource(rec, v) })
+		}
+
+		ch := s.subscribe()
+		defer s.unsubscribe(ch)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+	recs:
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case rec := <-ch:
+				for _, f := range filters {
+					if !f(rec) {
+						continue recs
+					}
+				}
+				b, err := json.Marshal(rec)
+				if err != nil {
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "data: %s\n\n", b); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package memsink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jasonhancock/go-logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinkStreamHandler(t *testing.T) {
+	sink := New(10)
+	l := logger.New(
+		logger.WithDestination(io.Discard),
+		logger.WithName("app"),
+		logger.WithLevel("debug"),
+		logger.WithCaller(false),
+		logger.WithRecordSink(sink.Record),
+	)
+	l.Warn("before connecting")
+
+	srv := httptest.NewServer(sink.StreamHandler())
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?level=warn&src=app.db", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// The subscription is in place once the headers have been received.
+	db := l.New("db")
+	db.Debug("db debug")
+	l.Warn("root warn")
+	db.Warn("db warn")
+
+	events := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			if data, ok := strings.CutPrefix(sc.Text(), "data: "); ok {
+				events <- data
+			}
+		}
+	}()
+
+	select {
+	case data := <-events:
+		var rec struct {
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(data), &rec))
+		require.Equal(t, "warn", rec.Level)
+		require.Equal(t, "db warn", rec.Msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	cancel()
+	require.Eventually(t, func() bool {
+		sink.mu.RLock()
+		defer sink.mu.RUnlock()
+		return len(sink.subs) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
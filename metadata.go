@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+type metadataKey struct{}
+
+// ContextWithMetadata returns a copy of ctx carrying the metadata entries in
+// kv, which are pairs of keys and values, in addition to the entries already
+// in ctx. Metadata is meant for business context set at the edge of a service,
+// such as a tenant or region; use WithMetadataAttrs to log it.
+func ContextWithMetadata(ctx context.Context, kv ...string) context.Context {
+	prev := MetadataFromContext(ctx)
+	md := make(map[string]string, len(prev)+len(kv)/2)
+	for k, v := range prev {
+		md[k] = v
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		md[kv[i]] = kv[i+1]
+	}
+	return context.WithValue(ctx, metadataKey{}, md)
+}
+
+// MetadataFromContext returns the metadata carried by ctx. The returned map
+// must not be modified.
+func MetadataFromContext(ctx context.Context) map[string]string {
+	md, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return md
+}
+
+// parseBaggage returns the entries of a W3C baggage header, which has the form
+// key1=value1;property,key2=value2, as pairs of keys and values. Properties
+// and malformed entries are ignored.
+func parseBaggage(h string) []string {
+	var kv []string
+	for _, member := range strings.Split(h, ",") {
+		member, _, _ = strings.Cut(member, ";")
+		k, v, ok := strings.Cut(member, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			continue
+		}
+		v, err := url.PathUnescape(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		kv = append(kv, k, v)
+	}
+	return kv
+}
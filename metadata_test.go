@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerMetadataAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false), WithName("app"), WithMetadataAttrs("tenant", "region"))
+
+	ctx := ContextWithMetadata(context.Background(), "region", "us-east", "plan", "pro")
+	ctx = ContextWithMetadata(ctx, "tenant", "acme")
+	l.InfoCtx(ctx, "foo")
+	l.InfoCtx(context.Background(), "bar")
+
+	require.Contains(t, buf.String(), "msg=foo src=app tenant=acme region=us-east\n")
+	require.Contains(t, buf.String(), "msg=bar src=app\n")
+}
+
+func TestMiddlewareBaggage(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false), WithName("app"), WithMetadataAttrs("tenant", "region"))
+
+	h := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).InfoCtx(r.Context(), "handling")
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("baggage", "tenant=acme%20corp;prop=1, region = eu ,invalid")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	require.Contains(t, buf.String(), `msg=handling src=app tenant="acme corp" region=eu`+"\n")
+}
+
+func TestParseBaggage(t *testing.T) {
+	require.Equal(t, []string{"a", "1", "b", "x y"}, parseBaggage("a=1, b=x%20y;p=q,=c,d,e=%zz"))
+	require.Nil(t, parseBaggage(""))
+}
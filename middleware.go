@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Attributes added to request-scoped loggers by Middleware.
+const (
+	traceIDKey      = "trace_id"
+	parentSpanIDKey = "parent_span_id"
+	traceStateKey   = "tracestate"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying l.
+func NewContext(ctx context.Context, l *L) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or nil if there is none. A nil
+// *L discards everything logged to it.
+func FromContext(ctx context.Context) *L {
+	l, _ := ctx.Value(contextKey{}).(*L)
+	return l
+}
+
+// Middleware returns an http.Handler that makes a request-scoped logger
+// available to next through FromContext(r.Context()). If the request carries a
+// valid W3C traceparent header, the logger has trace_id and parent_span_id
+// attributes, and a tracestate attribute if that header is set too, so records
+// can be correlated with traces without a tracing SDK, and the request context
+// records whether the trace is sampled, see WithTraceSampling. Entries of a W3C
+// baggage header are added to the request context's metadata, see
+// WithMetadataAttrs. Debug logging is forced for requests with the header set by
+// WithDebugHeader.
+func (l *L) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rl, ctx := l, r.Context()
+		if traceID, spanID, sampled, ok := parseTraceparent(r.Header.Get("traceparent")); ok {
+			keyvals := []any{traceIDKey, traceID, parentSpanIDKey, spanID}
+			if ts := r.Header.Get("tracestate"); ts != "" {
+				keyvals = append(keyvals, traceStateKey, ts)
+			}
+			rl = l.With(keyvals...)
+			ctx = ContextWithTraceSampled(ctx, sampled)
+		}
+		if l.debugRequested(r) {
+			rl = rl.ForceDebug()
+			ctx = ContextWithForceDebug(ctx)
+		}
+		ctx = NewContext(ctx, rl)
+		if b := r.Header.Get("baggage"); b != "" {
+			ctx = ContextWithMetadata(ctx, parseBaggage(b)...)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// parseTraceparent returns the trace and parent ids of a W3C traceparent header,
+// which has the form version-traceid-parentid-flags, e.g.
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01, and whether the
+// sampled flag is set.
+func parseTraceparent(h string) (traceID, spanID string, sampled, ok bool) {
+	h = strings.TrimSpace(h)
+	if len(h) < 55 {
+		return "", "", false, false
+	}
+	// Later versions may append fields, version 00 may not.
+	if len(h) > 55 && (h[0:2] == "00" || h[55] != '-') {
+		return "", "", false, false
+	}
+	if h[2] != '-' || h[35] != '-' || h[52] != '-' {
+		return "", "", false, false
+	}
+
+	version, traceID, spanID, flags := h[0:2], h[3:35], h[36:52], h[53:55]
+	if !isLowerHex(version) || version == "ff" || !isLowerHex(flags) {
+		return "", "", false, false
+	}
+	if !isLowerHex(traceID) || isZeros(traceID) || !isLowerHex(spanID) || isZeros(spanID) {
+		return "", "", false, false
+	}
+	// The sampled flag is the lowest bit of the last hex digit.
+	return traceID, spanID, strings.IndexByte("13579bdf", flags[1]) >= 0, true
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZeros(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
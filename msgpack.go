@@ -0,0 +1,290 @@
+package logger
+
+import (
+	"context"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// msgpackHandler is a slog.Handler that writes each record as a MessagePack map.
+// Groups are encoded as nested maps and times use the MessagePack timestamp
+// extension type.
+type msgpackHandler struct {
+	opts slog.HandlerOptions
+	mu   *sync.Mutex
+	w    io.Writer
+	goas []groupOrAttrs
+}
+
+// groupOrAttrs holds either a group name or a set of attributes added to a
+// handler via WithGroup or WithAttrs.
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+func newMsgpackHandler(w io.Writer, opts *slog.HandlerOptions) *msgpackHandler {
+	h := &msgpackHandler{mu: &sync.Mutex{}, w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+func (h *msgpackHandler) Enabled(_ context.Context, lvl slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return lvl >= minLevel
+}
+
+func (h *msgpackHandler) Handle(_ context.Context, r slog.Record) error {
+	var attrs []slog.Attr
+	if !r.Time.IsZero() {
+		attrs = h.appendAttr(attrs, nil, slog.Time(slog.TimeKey, r.Time))
+	}
+	attrs = h.appendAttr(attrs, nil, slog.Any(slog.LevelKey, r.Level))
+	attrs = h.appendAttr(attrs, nil, slog.String(slog.MessageKey, r.Message))
+	attrs = append(attrs, h.build(h.goas, nil, r)...)
+
+	buf := appendMsgpackMap(nil, attrs)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf)
+	return err
+}
+
+// build returns the attributes of the handler and record, nesting those added
+// after a call to WithGroup in a group attribute.
+func (h *msgpackHandler) build(goas []groupOrAttrs, groups []string, r slog.Record) []slog.Attr {
+	var out []slog.Attr
+	for i, goa := range goas {
+		if goa.group != "" {
+			inner := h.build(goas[i+1:], append(groups, goa.group), r)
+			if len(inner) > 0 {
+				out = append(out, slog.Attr{Key: goa.group, Value: slog.GroupValue(inner...)})
+			}
+			return out
+		}
+		for _, a := range goa.attrs {
+			out = h.appendAttr(out, groups, a)
+		}
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		out = h.appendAttr(out, groups, a)
+		return true
+	})
+	return out
+}
+
+// appendAttr resolves a and applies ReplaceAttr to it, following the same rules
+// as the slog handlers for empty attributes and groups.
+func (h *msgpackHandler) appendAttr(out []slog.Attr, groups []string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		var inner []slog.Attr
+		g := groups
+		if a.Key != "" {
+			g = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, ga := range a.Value.Group() {
+			inner = h.appendAttr(inner, g, ga)
+		}
+		if len(inner) == 0 {
+			return out
+		}
+		if a.Key == "" {
+			return append(out, inner...)
+		}
+		return append(out, slog.Attr{Key: a.Key, Value: slog.GroupValue(inner...)})
+	}
+
+	if h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(groups, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Equal(slog.Attr{}) || a.Key == "" {
+		return out
+	}
+
+	return append(out, a)
+}
+
+func (h *msgpackHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(groupOrAttrs{attrs: attrs})
+}
+
+func (h *msgpackHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(groupOrAttrs{group: name})
+}
+
+func (h *msgpackHandler) with(goa groupOrAttrs) *msgpackHandler {
+	c := *h
+	c.goas = append(h.goas[:len(h.goas):len(h.goas)], goa)
+	return &c
+}
+
+func appendMsgpackMap(b []byte, attrs []slog.Attr) []byte {
+	b = appendMsgpackHeader(b, len(attrs), 0x80, 0xdf)
+	for _, a := range attrs {
+		b = appendMsgpackString(b, a.Key)
+		b = appendMsgpackValue(b, a.Value)
+	}
+	return b
+}
+
+func appendMsgpackValue(b []byte, v slog.Value) []byte {
+	switch v.Kind() {
+	case slog.KindString:
+		return appendMsgpackString(b, v.String())
+	case slog.KindInt64:
+		return appendMsgpackInt(b, v.Int64())
+	case slog.KindUint64:
+		return appendMsgpackUint(b, v.Uint64())
+	case slog.KindFloat64:
+		return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(v.Float64()))
+	case slog.KindBool:
+		if v.Bool() {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case slog.KindDuration:
+		return appendMsgpackInt(b, int64(v.Duration()))
+	case slog.KindTime:
+		return appendMsgpackTime(b, v.Time())
+	case slog.KindGroup:
+		return appendMsgpackMap(b, v.Group())
+	default:
+		return appendMsgpackAny(b, v.Any())
+	}
+}
+
+func appendMsgpackAny(b []byte, v any) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case slog.Level:
+		return appendMsgpackString(b, v.String())
+	case []byte:
+		b = appendMsgpackHeader(b, len(v), 0, 0xc6)
+		return append(b, v...)
+	case error:
+		return appendMsgpackString(b, v.Error())
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return appendMsgpackString(b, "!ERROR:"+err.Error())
+		}
+		return appendMsgpackString(b, string(text))
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		b = appendMsgpackHeader(b, rv.Len(), 0x90, 0xdd)
+		for i := 0; i < rv.Len(); i++ {
+			b = appendMsgpackValue(b, slog.AnyValue(rv.Index(i).Interface()))
+		}
+		return b
+	case reflect.Map:
+		b = appendMsgpackHeader(b, rv.Len(), 0x80, 0xdf)
+		iter := rv.MapRange()
+		for iter.Next() {
+			b = appendMsgpackString(b, fmt.Sprint(iter.Key().Interface()))
+			b = appendMsgpackValue(b, slog.AnyValue(iter.Value().Interface()))
+		}
+		return b
+	default:
+		return appendMsgpackString(b, fmt.Sprintf("%+v", v))
+	}
+}
+
+// appendMsgpackHeader appends the header of a string, binary, array, or map of
+// length n. fix is the type byte of the fixed-length form, or 0 if there isn't
+// one, and wide is the type byte of the 32-bit length form. The 8 and 16-bit
+// forms immediately precede it, except for bin which has no fixed form.
+func appendMsgpackHeader(b []byte, n int, fix, wide byte) []byte {
+	switch {
+	case fix == 0x80 || fix == 0x90:
+		if n < 16 {
+			return append(b, fix|byte(n))
+		}
+		if n <= math.MaxUint16 {
+			return binary.BigEndian.AppendUint16(append(b, wide-1), uint16(n))
+		}
+	case fix == 0xa0:
+		if n < 32 {
+			return append(b, fix|byte(n))
+		}
+		fallthrough
+	default:
+		if n <= math.MaxUint8 {
+			return append(b, wide-2, byte(n))
+		}
+		if n <= math.MaxUint16 {
+			return binary.BigEndian.AppendUint16(append(b, wide-1), uint16(n))
+		}
+	}
+	return binary.BigEndian.AppendUint32(append(b, wide), uint32(n))
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	b = appendMsgpackHeader(b, len(s), 0xa0, 0xdb)
+	return append(b, s...)
+}
+
+func appendMsgpackInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return appendMsgpackUint(b, uint64(i))
+	case i >= -32:
+		return append(b, byte(i))
+	case i >= math.MinInt8:
+		return append(b, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(i))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(i))
+	}
+}
+
+func appendMsgpackUint(b []byte, u uint64) []byte {
+	switch {
+	case u <= 0x7f:
+		return append(b, byte(u))
+	case u <= math.MaxUint8:
+		return append(b, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(u))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), u)
+	}
+}
+
+// appendMsgpackTime appends t using the 96-bit form of the timestamp extension.
+func appendMsgpackTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xc7, 12, 0xff)
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+	return binary.BigEndian.AppendUint64(b, uint64(t.Unix()))
+}
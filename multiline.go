@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// multilineWriter moves long or multi-line values out of each logfmt record
+// written to it and renders them as indented blocks below the record:
+//
+//	level=err msg="query failed" src=app
+//	  query:
+//	    SELECT *
+//	    FROM users
+//
+// Values that are JSON objects or arrays are indented as well.
+type multilineWriter struct {
+	w         io.Writer
+	threshold int
+}
+
+func (w *multilineWriter) Write(p []byte) (int, error) {
+	line := bytes.TrimSuffix(p, []byte("\n"))
+	fields := splitLogfmt(line)
+	if fields == nil {
+		return w.w.Write(p)
+	}
+
+	var head, blocks []byte
+	for _, f := range fields {
+		v, ok := w.blockValue(logfmtValue(f))
+		if !ok {
+			if len(head) > 0 {
+				head = append(head, ' ')
+			}
+			head = append(head, f...)
+			continue
+		}
+
+		blocks = append(blocks, "  "...)
+		blocks = append(blocks, logfmtKey(f)...)
+		blocks = append(blocks, ":\n"...)
+		for _, l := range strings.Split(strings.TrimRight(v, "\n"), "\n") {
+			blocks = append(blocks, "    "...)
+			blocks = append(blocks, l...)
+			blocks = append(blocks, '\n')
+		}
+	}
+	if blocks == nil {
+		return w.w.Write(p)
+	}
+
+	out := append(append(head, '\n'), blocks...)
+	if _, err := w.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// blockValue returns the value to render as a block if raw should be moved out
+// of the record.
+func (w *multilineWriter) blockValue(raw []byte) (string, bool) {
+	v := string(raw)
+	if len(raw) > 0 && raw[0] == '"' {
+		uq, err := strconv.Unquote(v)
+		if err != nil {
+			return "", false
+		}
+		v = uq
+	}
+
+	if !strings.Contains(v, "\n") && (w.threshold <= 0 || len(v) <= w.threshold) {
+		return "", false
+	}
+
+	if t := strings.TrimSpace(v); strings.HasPrefix(t, "{") || strings.HasPrefix(t, "[") {
+		var buf bytes.Buffer
+		if json.Indent(&buf, []byte(t), "", "  ") == nil {
+			return buf.String(), true
+		}
+	}
+	return v, true
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerMultilineValues(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithCaller(false),
+		WithKeyNames(KeyNames{Time: KeyOmit}),
+		WithMultilineValues(20),
+	)
+
+	l.Info("query failed",
+		"query", "SELECT *\nFROM users\n",
+		"body", `{"id":1,"tags":["a"]}`,
+		"short", "ok",
+	)
+	l.Info("plain")
+
+	require.Equal(t, `level=info msg="query failed" src=go-logger.test short=ok
+  query:
+    SELECT *
+    FROM users
+  body:
+    {
+      "id": 1,
+      "tags": [
+        "a"
+      ]
+    }
+level=info msg=plain src=go-logger.test
+`, buf.String())
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNop(t *testing.T) {
+	l := Nop()
+
+	require.Nil(t, l.New("sub"))
+	require.Nil(t, l.With("key", "value"))
+	require.Nil(t, l.Without("key"))
+	require.Nil(t, l.Once("key"))
+	require.Nil(t, l.AddCallerSkip(1))
+
+	ctx := context.Background()
+	l.Debug("msg", "key", "value")
+	l.Info("msg", "key", "value")
+	l.Warn("msg", "key", "value")
+	l.Err("msg", "key", "value")
+	l.DebugCtx(ctx, "msg")
+	l.InfoCtx(ctx, "msg")
+	l.WarnCtx(ctx, "msg")
+	l.ErrCtx(ctx, "msg")
+	l.LogError("msg", errors.New("boom"))
+	l.Start("op").Done(nil)
+	l.DumpGoroutines()
+	require.NoError(t, l.Audit("login", "alice", "app"))
+	require.NoError(t, l.Close())
+	require.Equal(t, Stats{Records: map[string]uint64{}}, l.Stats())
+	l.TemporaryLevel("debug", time.Hour).Revert()
+	l.CaptureCmd(&exec.Cmd{Path: "true"}, "info")()
+
+	w := httptest.NewRecorder()
+	l.AdminHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Info("msg", "key", "value")
+		l.With("key", "value").Err("msg")
+	})
+	require.Zero(t, allocs)
+}
@@ -0,0 +1,244 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var errNotifierClosed = errors.New("notifier: closed, notification dropped")
+
+// Notification reasons.
+const (
+	NotifyFatal     = "fatal"
+	NotifyErrorRate = "error_rate"
+)
+
+// Notification describes why a notifier fired.
+type Notification struct {
+	Reason  string    `json:"reason"`
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source,omitempty"`
+	Message string    `json:"message"`
+	// Count is the number of errors logged within Window for NotifyErrorRate.
+	Count  int           `json:"count,omitempty"`
+	Window time.Duration `json:"window,omitempty"`
+}
+
+// NotifierConfig configures WithNotifier.
+type NotifierConfig struct {
+	// URL is the webhook notifications are posted to.
+	URL string
+
+	// Threshold is the number of error records within Window that triggers a
+	// notification. A value of 0 only notifies on fatal records.
+	Threshold int
+	Window    time.Duration
+
+	// MinInterval is the minimum time between error rate notifications. Defaults
+	// to 5 minutes. Fatal records are always notified.
+	MinInterval time.Duration
+
+	// Payload builds the request body for a notification. Defaults to the
+	// Notification encoded as JSON. Use SlackPayload for Slack incoming webhooks.
+	Payload func(Notification) ([]byte, error)
+
+	// Client is the HTTP client used to post notifications. Defaults to a client
+	// with a 10 second timeout.
+	Client *http.Client
+}
+
+// SlackPayload formats a notification as a Slack incoming webhook message.
+func SlackPayload(n Notification) ([]byte, error) {
+	var text string
+	switch n.Reason {
+	case NotifyFatal:
+		text = fmt.Sprintf(":rotating_light: *%s* exited with a fatal error: %s", n.Source, n.Message)
+	default:
+		text = fmt.Sprintf(":warning: *%s* logged %d errors in %s, latest: %s", n.Source, n.Count, n.Window, n.Message)
+	}
+	return json.Marshal(map[string]string{"text": text})
+}
+
+// notifier tracks the error rate and posts notifications in the background.
+type notifier struct {
+	cfg     NotifierConfig
+	onError func(error)
+
+	mu     sync.Mutex
+	errors []time.Time
+	last   time.Time
+	closed bool
+
+	queue chan Notification
+	wg    sync.WaitGroup
+}
+
+func newNotifier(cfg NotifierConfig, onError func(error)) *notifier {
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = 5 * time.Minute
+	}
+	if cfg.Payload == nil {
+		cfg.Payload = func(n Notification) ([]byte, error) { return json.Marshal(n) }
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	n := &notifier{
+		cfg:     cfg,
+		onError: onError,
+		queue:   make(chan Notification, 8),
+	}
+	n.wg.Add(1)
+	go n.run()
+	return n
+}
+
+// observe records an error or fatal record and queues a notification if one is
+// due.
+func (n *notifier) observe(r slog.Record, source string) {
+	note := Notification{
+		Time:    r.Time,
+		Source:  source,
+		Message: r.Message,
+	}
+
+	if r.Level >= LevelFatal {
+		note.Reason = NotifyFatal
+		n.enqueue(note)
+		return
+	}
+
+	if n.cfg.Threshold <= 0 {
+		return
+	}
+
+	n.mu.Lock()
+	cutoff := r.Time.Add(-n.cfg.Window)
+	i := 0
+	for i < len(n.errors) && !n.errors[i].After(cutoff) {
+		i++
+	}
+	n.errors = append(n.errors[i:], r.Time)
+
+	count := len(n.errors)
+	due := count >= n.cfg.Threshold && r.Time.Sub(n.last) >= n.cfg.MinInterval
+	if due {
+		n.last = r.Time
+		n.errors = n.errors[:0]
+	}
+	n.mu.Unlock()
+
+	if due {
+		note.Reason = NotifyErrorRate
+		note.Count = count
+		note.Window = n.cfg.Window
+		n.enqueue(note)
+	}
+}
+
+// enqueue queues note for sending, dropping it if the queue is full or the
+// notifier is closed, since records can still be logged after Close.
+func (n *notifier) enqueue(note Notification) {
+	n.mu.Lock()
+	closed := n.closed
+	if !closed {
+		select {
+		case n.queue <- note:
+		default:
+		}
+	}
+	n.mu.Unlock()
+
+	if closed && n.onError != nil {
+		n.onError(errNotifierClosed)
+	}
+}
+
+func (n *notifier) run() {
+	defer n.wg.Done()
+	for note := range n.queue {
+		if err := n.send(note); err != nil && n.onError != nil {
+			n.onError(err)
+		}
+	}
+}
+
+func (n *notifier) send(note Notification) error {
+	body, err := n.cfg.Payload(note)
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.cfg.Client.Post(n.cfg.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("notifier: %s", resp.Status)
+	}
+	return nil
+}
+
+// Close waits for queued notifications to be sent. Notifications enqueued
+// afterwards are dropped.
+func (n *notifier) Close() error {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+	n.wg.Wait()
+	return nil
+}
+
+// notifyHandler passes error and fatal records to a notifier in addition to the
+// inner handler.
+type notifyHandler struct {
+	inner     slog.Handler
+	notifier  *notifier
+	sourceKey string
+	source    string
+}
+
+func (h *notifyHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.inner.Enabled(ctx, lvl)
+}
+
+func (h *notifyHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelError {
+		h.notifier.observe(r, h.source)
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *notifyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.inner = h.inner.WithAttrs(attrs)
+	for _, a := range attrs {
+		if a.Key == h.sourceKey {
+			c.source = a.Value.String()
+		}
+	}
+	return &c
+}
+
+func (h *notifyHandler) WithGroup(name string) slog.Handler {
+	c := *h
+	c.inner = h.inner.WithGroup(name)
+	// Attributes in groups can't be the source.
+	c.sourceKey = ""
+	return &c
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerNotifier(t *testing.T) {
+	var mu sync.Mutex
+	var notes []Notification
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n Notification
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&n))
+		mu.Lock()
+		notes = append(notes, n)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := New(
+		WithDestination(io.Discard),
+		WithLevel("info"),
+		WithName("app"),
+		WithClock(func() time.Time { return now }),
+		WithNotifier(NotifierConfig{
+			URL:         srv.URL,
+			Threshold:   3,
+			Window:      time.Minute,
+			MinInterval: 10 * time.Minute,
+		}),
+	)
+
+	l.Err("one")
+	l.Info("ignored")
+	now = now.Add(2 * time.Minute)
+	// The first error has fallen out of the window.
+	l.Err("two")
+	l.Err("three")
+	l.New("db").Err("four")
+	// Suppressed by MinInterval.
+	l.Err("five")
+	l.Err("six")
+	l.Err("seven")
+	now = now.Add(11 * time.Minute)
+	l.Err("eight")
+	l.Err("nine")
+	l.Err("ten")
+
+	// Fatal without exiting.
+	l.log(context.Background(), LevelFatal, "boom")
+	require.NoError(t, l.Close())
+
+	require.Len(t, notes, 3)
+	require.Equal(t, NotifyErrorRate, notes[0].Reason)
+	require.Equal(t, 3, notes[0].Count)
+	require.Equal(t, "four", notes[0].Message)
+	require.Equal(t, "app.db", notes[0].Source)
+	require.Equal(t, time.Minute, notes[0].Window)
+	require.Equal(t, "ten", notes[1].Message)
+	require.Equal(t, NotifyFatal, notes[2].Reason)
+	require.Equal(t, "boom", notes[2].Message)
+	require.Equal(t, "app", notes[2].Source)
+}
+
+func TestLoggerNotifierAfterClose(t *testing.T) {
+	var reported []error
+	l := New(
+		WithDestination(io.Discard),
+		WithLevel("info"),
+		WithNotifier(NotifierConfig{URL: "http://127.0.0.1:1", Threshold: 1}),
+		WithErrorHandler(func(err error) { reported = append(reported, err) }),
+	)
+	require.NoError(t, l.Close())
+
+	l.Err("after close")
+	l.log(context.Background(), LevelFatal, "boom")
+	require.NoError(t, l.Close())
+	require.Equal(t, []error{errNotifierClosed, errNotifierClosed}, reported)
+}
+
+func TestSlackPayload(t *testing.T) {
+	b, err := SlackPayload(Notification{Reason: NotifyFatal, Source: "app", Message: "boom"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"text":":rotating_light: *app* exited with a fatal error: boom"}`, string(b))
+
+	b, err = SlackPayload(Notification{Reason: NotifyErrorRate, Source: "app", Message: "oops", Count: 5, Window: time.Minute})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"text":":warning: *app* logged 5 errors in 1m0s, latest: oops"}`, string(b))
+}
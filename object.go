@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Object returns a value that expands the exported fields of the struct v into a
+// group of attributes when logged:
+//
+//	l.Info("user created", "user", logger.Object(u))
+//
+// Field names can be overridden with a `log:"name"` struct tag, a field tagged
+// `log:"-"` is omitted, and `log:",omitempty"` omits the field when it has its
+// zero value. Nested structs are expanded into nested groups, down to 10
+// levels; a struct that refers back to one it is nested in is
+// logged as <cycle>, and those nested deeper as <too deep>. If v isn't a struct
+// or a pointer to one it is logged as is.
+func Object(v any) slog.LogValuer {
+	return object{v: v}
+}
+
+type object struct {
+	v any
+}
+
+func (o object) LogValue() slog.Value {
+	return objectValue(reflect.ValueOf(o.v), 0, nil)
+}
+
+// objectMaxDepth is the number of levels of nested structs Object expands.
+const objectMaxDepth = 10
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// objectPtr identifies a pointer followed by objectValue. The type is needed
+// because a struct and its first field share an address.
+type objectPtr struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// objectValue returns the value of rv, nested depth structs deep. seen holds the
+// pointers followed to reach rv, so that cycles aren't followed forever.
+func objectValue(rv reflect.Value, depth int, seen map[objectPtr]struct{}) slog.Value {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return slog.AnyValue(nil)
+		}
+		if rv.Kind() == reflect.Pointer {
+			p := objectPtr{addr: rv.Pointer(), typ: rv.Type()}
+			if _, ok := seen[p]; ok {
+				return slog.StringValue("<cycle>")
+			}
+			if seen == nil {
+				seen = make(map[objectPtr]struct{})
+			}
+			seen[p] = struct{}{}
+			defer delete(seen, p)
+		}
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return slog.AnyValue(nil)
+	}
+
+	if rv.Kind() != reflect.Struct || rv.Type() == timeType {
+		return slog.AnyValue(rv.Interface())
+	}
+	if depth >= objectMaxDepth {
+		return slog.StringValue("<too deep>")
+	}
+
+	rt := rv.Type()
+	attrs := make([]slog.Attr, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(f.Tag.Get("log"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fv := rv.Field(i)
+		if opts == "omitempty" && fv.IsZero() {
+			continue
+		}
+
+		if lv, ok := fv.Interface().(slog.LogValuer); ok {
+			attrs = append(attrs, slog.Any(name, lv))
+			continue
+		}
+		attrs = append(attrs, slog.Attr{Key: name, Value: objectValue(fv, depth+1, seen)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type objectAddress struct {
+	City string `log:"city"`
+	Zip  string `log:"zip,omitempty"`
+}
+
+type objectUser struct {
+	ID       int            `log:"id"`
+	Name     string         `log:"name"`
+	Password string         `log:"-"`
+	Email    string         `log:",omitempty"`
+	Address  *objectAddress `log:"address"`
+	Created  time.Time      `log:"created"`
+	Secret   secretValue    `log:"secret"`
+	internal string
+}
+
+type secretValue string
+
+func (s secretValue) LogValue() slog.Value {
+	return slog.StringValue("REDACTED")
+}
+
+type objectNode struct {
+	Name string      `log:"name"`
+	Next *objectNode `log:"next,omitempty"`
+	Peer *objectNode `log:"peer,omitempty"`
+}
+
+func TestObjectCycles(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("info"), WithFormat(FormatJSON))
+
+	decode := func() map[string]any {
+		var data struct {
+			Node map[string]any `json:"node"`
+		}
+		require.NoError(t, json.NewDecoder(&buf).Decode(&data))
+		buf.Reset()
+		return data.Node
+	}
+
+	t.Run("self", func(t *testing.T) {
+		n := &objectNode{Name: "a"}
+		n.Next = n
+		l.Info("cycle", "node", Object(n))
+		require.Equal(t, map[string]any{"name": "a", "next": "<cycle>"}, decode())
+	})
+
+	t.Run("loop", func(t *testing.T) {
+		a, b := &objectNode{Name: "a"}, &objectNode{Name: "b"}
+		a.Next, b.Next = b, a
+		l.Info("cycle", "node", Object(a))
+		require.Equal(t, map[string]any{"name": "a", "next": map[string]any{"name": "b", "next": "<cycle>"}}, decode())
+	})
+
+	t.Run("shared", func(t *testing.T) {
+		shared := &objectNode{Name: "shared"}
+		l.Info("shared", "node", Object(&objectNode{Name: "a", Next: shared, Peer: shared}))
+		require.Equal(t, map[string]any{
+			"name": "a",
+			"next": map[string]any{"name": "shared"},
+			"peer": map[string]any{"name": "shared"},
+		}, decode())
+	})
+
+	t.Run("deep", func(t *testing.T) {
+		var head *objectNode
+		for i := 0; i < objectMaxDepth+5; i++ {
+			head = &objectNode{Name: "n", Next: head}
+		}
+		l.Info("deep", "node", Object(head))
+
+		n := decode()
+		for i := 0; i < objectMaxDepth-1; i++ {
+			n = n["next"].(map[string]any)
+		}
+		require.Equal(t, "<too deep>", n["next"])
+	})
+}
+
+func TestObject(t *testing.T) {
+	var buf bytes.Buffer
+	created := time.Date(2023, 4, 13, 17, 38, 13, 0, time.UTC)
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithFormat(FormatJSON),
+	)
+
+	u := &objectUser{
+		ID:       42,
+		Name:     "alice",
+		Password: "hunter2",
+		Address:  &objectAddress{City: "Denver"},
+		Created:  created,
+		Secret:   "s3cr3t",
+		internal: "internal",
+	}
+
+	l.Info("user created", "user", Object(u))
+
+	var data struct {
+		User map[string]any `json:"user"`
+	}
+	require.NoError(t, json.NewDecoder(&buf).Decode(&data))
+	require.Equal(t, map[string]any{
+		"id":      float64(42),
+		"name":    "alice",
+		"address": map[string]any{"city": "Denver"},
+		"created": "2023-04-13T17:38:13Z",
+		"secret":  "REDACTED",
+	}, data.User)
+
+	t.Run("not a struct", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.Info("foo", "value", Object(42), "nil", Object((*objectUser)(nil)))
+		require.Contains(t, buf.String(), `"value":42`)
+		require.Contains(t, buf.String(), `"nil":null`)
+	})
+
+	t.Run("logfmt", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(WithDestination(&buf), WithLevel("info"))
+
+		l.Info("foo", "addr", Object(objectAddress{City: "Denver", Zip: "80202"}))
+		require.Contains(t, buf.String(), "addr.city=Denver addr.zip=80202")
+	})
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Operation is a timed operation started by (*L).Start.
+type Operation struct {
+	l       *L
+	msg     string
+	keyvals []any
+	start   time.Time
+}
+
+// Start begins timing an operation. Calling Done on the returned Operation logs
+// msg and keyvals along with the time elapsed since Start was called:
+//
+//	op := l.Start("rebuild index", "index", name)
+//	err := rebuild(name)
+//	op.Done(err)
+func (l *L) Start(msg string, keyvals ...any) *Operation {
+	op := &Operation{l: l, msg: msg, keyvals: keyvals}
+	if l != nil {
+		op.start = l.now()
+	}
+	return op
+}
+
+// Done logs the completion of the operation with a duration attribute. If err is
+// nil the message is logged at the info level, otherwise it is logged at the
+// error level along with the error.
+func (op *Operation) Done(err error, keyvals ...any) {
+	if op.l == nil {
+		return
+	}
+
+	lvl := slog.LevelInfo
+	if err != nil {
+		lvl = slog.LevelError
+	}
+
+	if !op.l.enabled(context.Background(), lvl) {
+		return
+	}
+
+	kv := make([]any, 0, len(op.keyvals)+len(keyvals)+2)
+	kv = append(kv, op.keyvals...)
+	kv = append(kv, keyvals...)
+	kv = append(kv, slog.Duration("duration", op.l.now().Sub(op.start)))
+	if err != nil {
+		kv = append(kv, slog.String("error", err.Error()))
+	}
+
+	op.l.log(context.Background(), lvl, op.msg, kv...)
+}
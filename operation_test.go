@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperation(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Now()
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithClock(func() time.Time { return now }),
+	)
+
+	t.Run("success", func(t *testing.T) {
+		defer buf.Reset()
+
+		op := l.Start("rebuild index", "index", "users")
+		now = now.Add(1500 * time.Millisecond)
+		op.Done(nil, "docs", 42)
+
+		require.Contains(t, buf.String(), "level=info")
+		require.Contains(t, buf.String(), `msg="rebuild index"`)
+		require.Contains(t, buf.String(), "index=users")
+		require.Contains(t, buf.String(), "docs=42")
+		require.Contains(t, buf.String(), "duration=1.5s")
+		require.Contains(t, buf.String(), "caller=github.com/jasonhancock/go-logger/operation_test.go")
+		require.NotContains(t, buf.String(), "error=")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		defer buf.Reset()
+
+		op := l.Start("rebuild index")
+		now = now.Add(time.Second)
+		op.Done(errors.New("disk full"))
+
+		require.Contains(t, buf.String(), "level=err")
+		require.Contains(t, buf.String(), "duration=1s")
+		require.Contains(t, buf.String(), `error="disk full"`)
+	})
+
+	t.Run("nil logger", func(t *testing.T) {
+		var l *L
+		l.Start("foo").Done(nil)
+	})
+}
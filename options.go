@@ -1,9 +1,17 @@
 package logger
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"reflect"
+	"runtime"
 	"runtime/debug"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,11 +23,120 @@ type options struct {
 	destination      io.Writer
 	showCaller       bool
 	callerPrefixTrim string
-	timeFormatter    TimeFormatterFunc
+	callerSkip       int
+	callerFormat     CallerFormat
+	callerLevel      string
+	timeLocation     *time.Location
+	timeLocationErr  error
+	timeLayout       string
+	clock            func() time.Time
+	keys             KeyNames
+	numericLevel     NumericLevel
+	numericLevelKey  string
+	seqKey           string
+	seq              *atomic.Uint64
+	recordIDKey      string
+	srcPathKey       string
+	replaceAttr      func(groups []string, a slog.Attr) slog.Attr
+	maxValueLength   int
+	maxRecordSize    int
+	maxAttrs         int
+	sanitize         bool
+	errorHandler     func(error)
+	compression      string
+	encryptionKey    []byte
+	sentryDSN        string
+	notifiers        []NotifierConfig
+	srcFilter        *sourceFilter
+	filters          []Filter
+	registry         *Registry
+	errorCodes       bool
+	errorVerbose     bool
+	durationFormat   DurationFormat
+	keyOrder         []string
+	color            ColorMode
+	multiline        bool
+	multilineLen     int
+	tees             []tee
+	handlers         []slog.Handler
+	splitDest        io.Writer
+	serialize        bool
+	writeTimeout     time.Duration
+	splitLevel       string
+	recordSinks      []func(slog.Record)
+	namedSinks       []namedSink
+	routes           []Route
+	sampleRates      map[string]float64
+	adaptiveRate     int
+	adaptiveSummary  time.Duration
+	traceSampled     func(context.Context) bool
+	debugHeader      string
+	debugAuthorize   func(*http.Request) bool
+	cardinalityLimit int
+	cardinalityMode  CardinalityAction
+	cardinalityKeys  []string
+	repanic          bool
+	dumpOnFatal      bool
+	dumpWriter       io.Writer
+	fallback         io.Writer
+	dedupWindow      time.Duration
+	auditDestination io.Writer
+	auditHashChain   bool
+	auditHashKey     []byte
+	cloudEventsType  string
+	attrs            []slog.Attr
+	extractors       []ContextExtractor
+	dedupeAttrs      bool
+	strictKeys       bool
+	strictPanic      bool
+	marshalers       []marshaler
 }
 
+// TimeFormatterFunc formats a time. The logger doesn't use it.
+//
+// Deprecated: Use WithTimeFormat to customize how times are formatted.
 type TimeFormatterFunc func(time.Time) string
 
+// ContextExtractor returns attributes to add to a log message from values stored
+// in the context passed to the logger's Ctx methods.
+type ContextExtractor func(ctx context.Context) []slog.Attr
+
+// KeyOmit can be used as a field of KeyNames to omit that attribute from every
+// log message.
+const KeyOmit = "-"
+
+// KeyNames specifies the keys of the attributes the logger attaches to every log
+// message. Empty fields use the default key names of ts, msg, level, src, and
+// caller.
+type KeyNames struct {
+	Time    string
+	Message string
+	Level   string
+	Source  string
+	Caller  string
+}
+
+func (k KeyNames) withDefaults() KeyNames {
+	defaults := []struct {
+		key *string
+		def string
+	}{
+		{&k.Time, "ts"},
+		{&k.Message, slog.MessageKey},
+		{&k.Level, slog.LevelKey},
+		{&k.Source, "src"},
+		{&k.Caller, "caller"},
+	}
+
+	for _, d := range defaults {
+		if *d.key == "" {
+			*d.key = d.def
+		}
+	}
+
+	return k
+}
+
 // Option is used to customize the logger.
 type Option func(*options)
 
@@ -68,12 +185,106 @@ func WithCaller(showCaller bool) Option {
 	}
 }
 
-// WithTimeLocation specifies the locale to log the time in.
+// WithCallerSkip sets the number of additional stack frames to skip when
+// determining the caller. See (*L).AddCallerSkip.
+func WithCallerSkip(n int) Option {
+	return func(o *options) {
+		o.callerSkip = n
+	}
+}
+
+// WithCallerFormat sets how the caller of each log message is reported.
+// Defaults to CallerFile.
+func WithCallerFormat(f CallerFormat) Option {
+	return func(o *options) {
+		o.callerFormat = f
+	}
+}
+
+// WithCallerMinLevel only includes the caller in log messages at or above the
+// specified level, avoiding the cost of determining the caller for lower levels.
+func WithCallerMinLevel(level string) Option {
+	return func(o *options) {
+		o.callerLevel = level
+	}
+}
+
+// WithTimeLocation specifies the locale to log the time in. Defaults to UTC.
 func WithTimeLocation(loc *time.Location) Option {
 	return func(o *options) {
-		o.timeFormatter = func(ts time.Time) string {
-			return ts.In(loc).Format(time.RFC3339Nano)
+		o.timeLocation = loc
+	}
+}
+
+// WithTimeLocationName specifies the locale to log the time in by its IANA Time
+// Zone database name, such as "America/Denver", or "Local" for the system's
+// local time zone. If the location can't be loaded, NewE and MustNew report the
+// error and New logs the time in UTC.
+func WithTimeLocationName(name string) Option {
+	return func(o *options) {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			o.timeLocation = time.UTC
+			o.timeLocationErr = fmt.Errorf("logger: loading time location: %w", err)
+			return
+		}
+		o.timeLocation = loc
+		o.timeLocationErr = nil
+	}
+}
+
+// WithLocalTime logs the time in the system's local time zone, which is taken
+// from the TZ environment variable if it is set.
+func WithLocalTime() Option {
+	return WithTimeLocation(time.Local)
+}
+
+// Time formats that can be passed to WithTimeFormat in addition to any layout
+// understood by time.Time.Format.
+const (
+	// TimeFormatRFC3339Milli is RFC3339 with millisecond precision.
+	TimeFormatRFC3339Milli = "2006-01-02T15:04:05.000Z07:00"
+	// TimeFormatUnix logs the time as a number of seconds since the Unix epoch.
+	TimeFormatUnix = "unix"
+	// TimeFormatUnixMilli logs the time as a number of milliseconds since the
+	// Unix epoch.
+	TimeFormatUnixMilli = "unixmilli"
+)
+
+// WithTimeFormat specifies the layout used to format the time of each log
+// message. Defaults to time.RFC3339Nano.
+func WithTimeFormat(layout string) Option {
+	return func(o *options) {
+		o.timeLayout = layout
+	}
+}
+
+// timeFormatter returns the function used to render the time attribute, or nil
+// if the handler's default rendering should be used.
+func (o *options) timeFormatter() func(time.Time) slog.Value {
+	loc, layout := o.timeLocation, o.timeLayout
+
+	if loc == nil {
+		// Detect if the current Location is UTC or not. If not, install the formatter.
+		// This is an optimization because servers should be set to UTC.
+		ts := time.Now()
+		if layout == "" && ts.Format(time.RFC3339Nano) == ts.In(time.UTC).Format(time.RFC3339Nano) {
+			return nil
 		}
+		loc = time.UTC
+	}
+
+	switch layout {
+	case TimeFormatUnix:
+		return func(ts time.Time) slog.Value { return slog.Int64Value(ts.Unix()) }
+	case TimeFormatUnixMilli:
+		return func(ts time.Time) slog.Value { return slog.Int64Value(ts.UnixMilli()) }
+	case "":
+		layout = time.RFC3339Nano
+	}
+
+	return func(ts time.Time) slog.Value {
+		return slog.StringValue(ts.In(loc).Format(layout))
 	}
 }
 
@@ -100,3 +311,656 @@ func WithAutoCallerPrefixTrim() Option {
 
 	return WithCallerPrefixTrim(bi.Main.Path)
 }
+
+// WithClock sets the function used to obtain the timestamp of each log message.
+// This is primarily useful for freezing the ts attribute in tests.
+func WithClock(clock func() time.Time) Option {
+	return func(o *options) {
+		if clock != nil {
+			o.clock = clock
+		}
+	}
+}
+
+// WithKeyNames renames the attributes the logger attaches to every log message.
+// Set a field to KeyOmit to drop that attribute entirely.
+func WithKeyNames(keys KeyNames) Option {
+	return func(o *options) {
+		o.keys = keys
+	}
+}
+
+// processSeq numbers the records of loggers created with WithProcessSequence.
+var processSeq atomic.Uint64
+
+// WithSequence adds an attribute named key, or seq if key is empty, to every
+// record holding a number that increases by one with each record logged by the
+// logger and the loggers derived from it with New and With. A gap in the
+// numbers of shipped records reveals that records were lost or reordered on the
+// way. Records suppressed by the logger itself, such as duplicates, also leave
+// gaps, while those rejected by filters do not.
+func WithSequence(key string) Option {
+	return func(o *options) {
+		if key == "" {
+			key = "seq"
+		}
+		o.seqKey = key
+		o.seq = new(atomic.Uint64)
+	}
+}
+
+// WithProcessSequence is like WithSequence, but the numbers are shared by every
+// logger in the process created with WithProcessSequence.
+func WithProcessSequence(key string) Option {
+	return func(o *options) {
+		WithSequence(key)(o)
+		o.seq = &processSeq
+	}
+}
+
+// WithSourcePath adds an attribute named key, or src_path if key is empty,
+// holding the logger's name and those of the sub-loggers created with New as an
+// array, such as ["api","db","tx"], which is easier to query than the dotted
+// source attribute. Each record holds only the path of the logger that logged
+// it. To log it instead of the source attribute, also pass
+// WithKeyNames with Source set to KeyOmit.
+func WithSourcePath(key string) Option {
+	return func(o *options) {
+		if key == "" {
+			key = "src_path"
+		}
+		o.srcPathKey = key
+	}
+}
+
+// WithRecordIDs adds a record_id attribute holding a ULID to every record, so
+// that individual records can be referenced, such as from tickets and error
+// reports. ULIDs sort by the time of the record they were generated for.
+func WithRecordIDs() Option {
+	return func(o *options) {
+		o.recordIDKey = recordIDKey
+	}
+}
+
+// WithNumericLevel renders the level of each log message as a number. If key is
+// empty, the number replaces the level name. Otherwise the level name is kept
+// and the number is added as an additional attribute named key.
+func WithNumericLevel(n NumericLevel, key string) Option {
+	return func(o *options) {
+		o.numericLevel = n
+		o.numericLevelKey = key
+	}
+}
+
+// WithReplaceAttr installs a function that is called to rewrite each attribute
+// before it is logged. It runs after the built-in handling of the time and level
+// attributes, so it sees their final keys and values. See
+// slog.HandlerOptions.ReplaceAttr for details.
+func WithReplaceAttr(fn func(groups []string, a slog.Attr) slog.Attr) Option {
+	return func(o *options) {
+		o.replaceAttr = fn
+	}
+}
+
+// WithMaxValueLength truncates string values longer than n bytes, appending an
+// ellipsis and the original length so that accidentally logging a huge payload
+// can't overwhelm downstream parsers. A value of 0 disables truncation.
+func WithMaxValueLength(n int) Option {
+	return func(o *options) {
+		o.maxValueLength = n
+	}
+}
+
+// WithMaxRecordSize limits the size in bytes of each serialized log message.
+// Attributes passed at the call site are dropped from the end of the message
+// until it fits, and a _truncated=true attribute is added in their place. A
+// value of 0 disables the limit.
+func WithMaxRecordSize(n int) Option {
+	return func(o *options) {
+		o.maxRecordSize = n
+	}
+}
+
+// WithMaxAttrs limits the number of attributes passed at the call site of each
+// log message. Excess attributes are dropped and a _truncated=true attribute is
+// added in their place. A value of 0 disables the limit.
+func WithMaxAttrs(n int) Option {
+	return func(o *options) {
+		o.maxAttrs = n
+	}
+}
+
+// WithSanitize escapes newlines, carriage returns, and other control characters
+// and removes ANSI escape sequences from the message and string values, so that
+// logged data can't forge additional records in line-oriented destinations.
+func WithSanitize() Option {
+	return func(o *options) {
+		o.sanitize = true
+	}
+}
+
+// WithErrorHandler sets a function that is called whenever writing a log message
+// to the destination fails.
+func WithErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.errorHandler = fn
+	}
+}
+
+// WithCompression compresses everything written to the destination using the
+// named algorithm. Only CompressionGzip is supported; zstd isn't, since it would
+// add a dependency outside the standard library. NewE rejects other algorithms,
+// and New reports them to the error handler and leaves the output uncompressed.
+// Compressed output is buffered, so Close must be called before the program
+// exits for the stream to be complete; Fatal does this automatically.
+func WithCompression(alg string) Option {
+	return func(o *options) {
+		o.compression = alg
+	}
+}
+
+// WithEncryption encrypts everything written to the destination with AES-GCM
+// using key, which must be 16, 24, or 32 bytes long. Each record is sealed in its
+// own frame, so partially written output can still be decrypted with
+// DecryptStream. If the key is invalid, nothing is written.
+func WithEncryption(key []byte) Option {
+	return func(o *options) {
+		o.encryptionKey = key
+	}
+}
+
+// WithSentry forwards records at the error level and above to Sentry as events,
+// in addition to logging them as usual. Attributes are sent as tags when they are
+// short strings and as extra data otherwise, and the stack of the logging call is
+// attached. Events are sent in the background; Close waits for them to be
+// delivered. Failures, including an invalid DSN, are reported to the error
+// handler.
+func WithSentry(dsn string) Option {
+	return func(o *options) {
+		o.sentryDSN = dsn
+	}
+}
+
+// WithNotifier posts to a webhook when a fatal record is logged or when error
+// records exceed a threshold within a window, subject to a minimum interval
+// between notifications. It can be used more than once to notify several
+// webhooks. Failures are reported to the error handler.
+func WithNotifier(cfg NotifierConfig) Option {
+	return func(o *options) {
+		o.notifiers = append(o.notifiers, cfg)
+	}
+}
+
+// WithSourceFilter mutes loggers based on their dotted src chain. If allow is
+// not empty, only loggers matching one of its patterns log anything; loggers
+// matching a pattern in deny never do. A * in a pattern matches any characters,
+// and patterns may omit the application name, so db.* matches myapp.db.conn.
+// Audit events are not filtered.
+func WithSourceFilter(allow, deny []string) Option {
+	return func(o *options) {
+		o.srcFilter = &sourceFilter{allow: allow, deny: deny}
+	}
+}
+
+// WithSampling keeps only a fraction of the records at some levels, such as
+//
+//	logger.WithSampling(map[string]float64{"debug": 0.01, "info": 0.1})
+//
+// to keep 1% of debug and 10% of info records, and every record at other
+// levels. Rates apply to records at exactly the named level and are shared by
+// the loggers derived from the logger. Records are kept evenly, so a rate of
+// 0.1 keeps every tenth record, and those dropped are counted in Stats.Dropped.
+func WithSampling(rates map[string]float64) Option {
+	return func(o *options) {
+		o.sampleRates = rates
+	}
+}
+
+// WithAdaptiveSampling keeps the number of debug and info records logged each
+// second to about perSecond, sampling those with the same level and message
+// at a rate adjusted every second so that frequent messages are sampled more
+// heavily than rare ones. Records at warn and above are never sampled. Every
+// summaryInterval, if it is positive, warnings are logged with the number of
+// records suppressed for each level and message. Suppressed records are also
+// counted in Stats.Dropped.
+func WithAdaptiveSampling(perSecond int, summaryInterval time.Duration) Option {
+	return func(o *options) {
+		o.adaptiveRate = perSecond
+		o.adaptiveSummary = summaryInterval
+	}
+}
+
+// WithTraceSampling logs every record of requests whose trace is sampled,
+// regardless of the level and of WithSampling and WithAdaptiveSampling, so that
+// detailed logs exist for exactly the traces that are kept. It only applies to
+// records logged with a context, such as with DebugCtx. sampled reports whether
+// the trace of a context is sampled; if it is nil, TraceSampled is used, which
+// works with Middleware. With OpenTelemetry, use
+//
+//	logger.WithTraceSampling(func(ctx context.Context) bool {
+//		return trace.SpanContextFromContext(ctx).IsSampled()
+//	})
+func WithTraceSampling(sampled func(context.Context) bool) Option {
+	return func(o *options) {
+		if sampled == nil {
+			sampled = TraceSampled
+		}
+		o.traceSampled = sampled
+	}
+}
+
+// WithDebugHeader makes Middleware force debug logging, see ForceDebug, for
+// requests setting header to a true value, such as X-Debug-Log: 1, that
+// authorize accepts, so that a request can be followed in detail in production
+// without lowering the level for every other request. The request context is
+// marked too, see ContextWithForceDebug, for loggers other than the one
+// returned by FromContext. authorize is required, since debug records can hold
+// details that anyone able to send the header shouldn't be able to produce.
+func WithDebugHeader(header string, authorize func(*http.Request) bool) Option {
+	return func(o *options) {
+		o.debugHeader = header
+		o.debugAuthorize = authorize
+	}
+}
+
+// WithCardinalityLimit guards against attributes with too many distinct
+// values, such as raw UUIDs under a key that a log backend indexes. Once an
+// attribute has had limit distinct values, records with new values have them
+// replaced or dropped according to action, and the error handler set with
+// WithErrorHandler is called. Only the named attributes are guarded, or, if
+// none are named, every top level attribute other than those the logger adds
+// itself, such as the time, message, and caller.
+func WithCardinalityLimit(limit int, action CardinalityAction, keys ...string) Option {
+	return func(o *options) {
+		o.cardinalityLimit = limit
+		o.cardinalityMode = action
+		o.cardinalityKeys = keys
+	}
+}
+
+// WithFilter only writes records matched by all of filters. Filters run after
+// the level check, so the level must be low enough to let through everything a
+// filter may want to keep, e.g. with
+//
+//	WithLevel("debug"),
+//	WithFilter(Or(LevelAtLeast(slog.LevelWarn), AttrEquals("user_id", 42))),
+//
+// debug and info records are only written for user 42. Audit events are not
+// filtered.
+func WithFilter(filters ...Filter) Option {
+	return func(o *options) {
+		o.filters = append(o.filters, filters...)
+	}
+}
+
+// WithRegistry tracks the logger and every sub-logger created from it with New
+// in reg, so their levels can be changed at runtime. The level set with
+// WithLevel becomes the root logger's level in reg unless reg already has one.
+func WithRegistry(reg *Registry) Option {
+	return func(o *options) {
+		o.registry = reg
+	}
+}
+
+// WithErrorCodes requires records at the error level and above to carry a code
+// attribute so they can be aggregated on a stable identifier rather than their
+// message. If the code isn't passed explicitly, it is derived from an error
+// attribute, or the error passed to LogError, implementing ErrorCoder. Records
+// without a code, or with a code that wasn't declared with RegisterErrorCode,
+// get a logging_error attribute describing the problem.
+func WithErrorCodes() Option {
+	return func(o *options) {
+		o.errorCodes = true
+	}
+}
+
+// WithErrorVerbose makes LogError also log an error_chain attribute listing
+// the type and message of the error and each error it wraps, and, for errors
+// that format differently with %+v, such as those carrying a stack trace, an
+// error_verbose attribute holding that rendering.
+func WithErrorVerbose() Option {
+	return func(o *options) {
+		o.errorVerbose = true
+	}
+}
+
+// WithDurationFormat sets how time.Duration values are rendered. By default
+// they are human readable in logfmt and integer nanoseconds in JSON.
+func WithDurationFormat(f DurationFormat) Option {
+	return func(o *options) {
+		o.durationFormat = f
+	}
+}
+
+// WithKeyOrder moves the named attributes to the front of each logfmt record, in
+// the given order, e.g. WithKeyOrder("level", "ts", "msg") to start every line
+// with the level. Keys are the names as logged, after any renaming with
+// WithKeyNames. It has no effect on other formats.
+func WithKeyOrder(keys ...string) Option {
+	return func(o *options) {
+		o.keyOrder = keys
+	}
+}
+
+// WithColor colors the level of each logfmt record, and the message of error
+// and fatal records, using ANSI escape sequences. It has no effect on other
+// formats. Defaults to ColorNever.
+func WithColor(mode ColorMode) Option {
+	return func(o *options) {
+		o.color = mode
+	}
+}
+
+// WithMultilineValues renders values containing newlines, such as stack traces
+// and SQL, as indented blocks below each logfmt record instead of escaping them
+// into a single line. If threshold is positive, values longer than threshold
+// bytes are rendered the same way, and values holding JSON are indented. This is
+// intended for reading logs in a console; the output is no longer one record
+// per line. It has no effect on other formats.
+func WithMultilineValues(threshold int) Option {
+	return func(o *options) {
+		o.multiline = true
+		o.multilineLen = threshold
+	}
+}
+
+// WithTee also writes every record to w in the given format, in addition to the
+// destination. It can be used more than once. Options that alter the output
+// bytes, such as WithColor, WithCompression, or WithKeyOrder, only apply to the
+// destination.
+func WithTee(w io.Writer, format string) Option {
+	return func(o *options) {
+		o.tees = append(o.tees, tee{w: w, format: format})
+	}
+}
+
+// WithNamedSink registers w as a destination named name that records are
+// written to, in the given format, only when logged by a logger returned by
+// (*L).To, in addition to the destination. Like WithTee, options that alter the
+// output bytes only apply to the destination.
+func WithNamedSink(name string, w io.Writer, format string) Option {
+	return func(o *options) {
+		o.namedSinks = append(o.namedSinks, namedSink{name: name, w: w, format: format})
+	}
+}
+
+// WithRoute sends the records matching r to one of the sinks registered with
+// WithNamedSink, such as errors from the payments logger to a dedicated file:
+//
+//	logger.WithNamedSink("payments", f, logger.FormatJSON),
+//	logger.WithRoute(logger.Route{Sink: "payments", Level: "err", Source: "payments.*"}),
+//
+// It can be used more than once; a record matching several routes to the same
+// sink is written to it once.
+func WithRoute(r Route) Option {
+	return func(o *options) {
+		o.routes = append(o.routes, r)
+	}
+}
+
+// WithSerializedWrites guards the destination, and the one set by
+// WithLevelSplit, with a mutex so that writes made by the logger never overlap,
+// even between regular records and audit events, which are encoded separately.
+// Use it for destinations that aren't safe for concurrent use.
+func WithSerializedWrites() Option {
+	return func(o *options) {
+		o.serialize = true
+	}
+}
+
+// WithWriteTimeout drops records that the destination doesn't accept within d,
+// such as a socket to a degraded log backend, rather than blocking the
+// application. Dropped records are reported to the error handler as
+// ErrWriteTimeout, written to the fallback destination if there is one, and
+// counted in Stats. Records that the destination accepts but doesn't finish
+// writing within d are reported as ErrWriteDelayed and are still written once it
+// unblocks. Writes are made from a separate goroutine, which is stopped by
+// Close.
+func WithWriteTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.writeTimeout = d
+	}
+}
+
+// WithLevelSplit writes records at or above level to w instead of the
+// destination. Options that alter the output bytes, such as WithCompression,
+// apply to both.
+func WithLevelSplit(w io.Writer, level string) Option {
+	return func(o *options) {
+		o.splitDest = w
+		o.splitLevel = level
+	}
+}
+
+// WithHandler also passes every record to h, in addition to the destination,
+// which allows forwarding records to another logging pipeline. For example, to
+// emit to an OpenTelemetry LoggerProvider using the otelslog bridge:
+//
+//	logger.WithHandler(otelslog.NewHandler("my-service", otelslog.WithLoggerProvider(provider)))
+//
+// Records reach h with the attributes the logger adds, such as src and caller,
+// but before any of the options formatting values for output are applied. It
+// can be used more than once.
+func WithHandler(h slog.Handler) Option {
+	return func(o *options) {
+		o.handlers = append(o.handlers, h)
+	}
+}
+
+// WithRecordSink calls fn with every record that is written, including audit
+// events, before it is encoded. The record holds the attributes added to the
+// logger followed by those passed at the call site and the ones the logger adds,
+// such as the caller, so tests and in-process consumers can inspect structured
+// data without parsing the output. fn must not retain the record without calling
+// its Clone method.
+func WithRecordSink(fn func(slog.Record)) Option {
+	return func(o *options) {
+		o.recordSinks = append(o.recordSinks, fn)
+	}
+}
+
+// WithRepanic makes Recover, and so goroutines started with Go, panic again with the
+// recovered value after logging it, so the panic still crashes the program.
+func WithRepanic() Option {
+	return func(o *options) {
+		o.repanic = true
+	}
+}
+
+// WithGoroutineDump makes Fatal dump the stacks of all goroutines before the
+// program exits, to help diagnose processes that were stuck. If w is nil the
+// dump is logged as a record; otherwise it is written to w, such as a file,
+// which also applies to DumpGoroutines.
+func WithGoroutineDump(w io.Writer) Option {
+	return func(o *options) {
+		o.dumpOnFatal = true
+		o.dumpWriter = w
+	}
+}
+
+// WithFallbackDestination sets a destination that log messages are written to
+// when writing to the primary destination fails, such as os.Stderr.
+func WithFallbackDestination(w io.Writer) Option {
+	return func(o *options) {
+		o.fallback = w
+	}
+}
+
+// WithDeduplication collapses consecutive identical log messages logged within
+// window of each other. The first message is logged as usual and, once the run of
+// duplicates ends, the window elapses, or the logger is closed, a single summary
+// is logged: the last duplicate with a repeated attribute holding the number of
+// duplicates. Messages from loggers derived with the same attributes, such as
+// per-request loggers, are compared as if logged by the same logger.
+func WithDeduplication(window time.Duration) Option {
+	return func(o *options) {
+		o.dedupWindow = window
+	}
+}
+
+// WithAuditDestination sets the target for where audit events logged via
+// (*L).Audit should be written. Defaults to the logger's destination.
+func WithAuditDestination(w io.Writer) Option {
+	return func(o *options) {
+		o.auditDestination = w
+	}
+}
+
+// WithAuditHashChain adds a hash attribute to each audit event covering both the
+// event and the hash of the previous event, so that modifications to the audit
+// trail can be detected with VerifyAuditChain. If key is non-nil, HMAC-SHA256 is
+// used instead of SHA-256 so that the chain can't be recomputed without the key.
+// The first event written by each process starts a new chain, marked with a
+// chain_start attribute, so that the destination can be appended to across
+// restarts. This should be combined with WithAuditDestination so that the destination
+// only contains audit events.
+func WithAuditHashChain(key []byte) Option {
+	return func(o *options) {
+		o.auditHashChain = true
+		o.auditHashKey = key
+	}
+}
+
+// WithCloudEventsType sets the CloudEvents type attribute used when the format
+// is FormatCloudEvents. Defaults to DefaultCloudEventsType.
+func WithCloudEventsType(typ string) Option {
+	return func(o *options) {
+		o.cloudEventsType = typ
+	}
+}
+
+// cloudEventsWriter wraps w so that each record is written as a CloudEvent.
+func (o *options) cloudEventsWriter(w io.Writer, keys KeyNames) io.Writer {
+	typ := o.cloudEventsType
+	if typ == "" {
+		typ = DefaultCloudEventsType
+	}
+	return &cloudEventsWriter{w: w, typ: typ, sourceKey: keys.Source, timeKey: keys.Time}
+}
+
+// WithBuildInfo adds the go_version, vcs_revision, and vcs_dirty attributes
+// describing the running binary to every log message. The VCS attributes are
+// only added if the binary was built with VCS stamping.
+func WithBuildInfo() Option {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok || bi == nil {
+		return func(o *options) {}
+	}
+
+	return func(o *options) {
+		o.attrs = append(o.attrs, buildInfoAttrs(bi)...)
+	}
+}
+
+func buildInfoAttrs(bi *debug.BuildInfo) []slog.Attr {
+	attrs := []slog.Attr{slog.String("go_version", bi.GoVersion)}
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			rev := s.Value
+			if len(rev) > 12 {
+				rev = rev[:12]
+			}
+			attrs = append(attrs, slog.String("vcs_revision", rev))
+		case "vcs.modified":
+			attrs = append(attrs, slog.Bool("vcs_dirty", s.Value == "true"))
+		}
+	}
+
+	return attrs
+}
+
+// WithVersion adds a version attribute to every log message.
+func WithVersion(v string) Option {
+	return func(o *options) {
+		o.attrs = append(o.attrs, slog.String("version", v))
+	}
+}
+
+// WithHostInfo adds the hostname and pid attributes identifying the host and
+// process to every log message.
+func WithHostInfo() Option {
+	return func(o *options) {
+		if hostname, err := os.Hostname(); err == nil {
+			o.attrs = append(o.attrs, slog.String("hostname", hostname))
+		}
+		o.attrs = append(o.attrs, slog.Int("pid", os.Getpid()))
+	}
+}
+
+// WithPlatformInfo adds the goos and goarch attributes identifying the platform
+// the binary was built for to every log message.
+func WithPlatformInfo() Option {
+	return func(o *options) {
+		o.attrs = append(o.attrs,
+			slog.String("goos", runtime.GOOS),
+			slog.String("goarch", runtime.GOARCH),
+		)
+	}
+}
+
+// WithContextExtractor adds a function that extracts attributes from the context
+// passed to the logger's Ctx methods, such as InfoCtx, so that values like
+// request IDs are attached to every log message without repeating them at each
+// call site. It can be specified multiple times.
+func WithContextExtractor(fn ContextExtractor) Option {
+	return func(o *options) {
+		o.extractors = append(o.extractors, fn)
+	}
+}
+
+// WithMetadataAttrs adds the metadata entries with the given keys, when present
+// in the context passed to the logger's Ctx methods, as attributes of every log
+// message. Middleware populates the metadata from the W3C baggage header.
+func WithMetadataAttrs(keys ...string) Option {
+	return WithContextExtractor(func(ctx context.Context) []slog.Attr {
+		md := MetadataFromContext(ctx)
+		if len(md) == 0 {
+			return nil
+		}
+		var attrs []slog.Attr
+		for _, k := range keys {
+			if v, ok := md[k]; ok {
+				attrs = append(attrs, slog.String(k, v))
+			}
+		}
+		return attrs
+	})
+}
+
+// WithAttrDeduplication ensures each key appears at most once per log message.
+// When the same key is supplied more than once, such as via With and again at the
+// call site, the most recently supplied value wins.
+func WithAttrDeduplication() Option {
+	return func(o *options) {
+		o.dedupeAttrs = true
+	}
+}
+
+// WithStrictKeys validates the keyvals passed to each logging method and to
+// With. Instead of slog's !BADKEY attribute, an odd number of keyvals or a key
+// that isn't a string results in a logging_error attribute identifying the
+// offending call site, and the malformed keyvals are dropped, or a panic if
+// panics is true so that mistakes are caught during development and in CI.
+func WithStrictKeys(panics bool) Option {
+	return func(o *options) {
+		o.strictKeys = true
+		o.strictPanic = panics
+	}
+}
+
+// WithMarshaler registers a function used to convert values of type T before
+// they are logged, instead of relying on the handler's default encoding. T may
+// be an interface type, in which case fn is used for all values implementing it.
+// For example, to log durations as milliseconds:
+//
+//	logger.WithMarshaler(func(d time.Duration) any { return d.Milliseconds() })
+func WithMarshaler[T any](fn func(T) any) Option {
+	return func(o *options) {
+		o.marshalers = append(o.marshalers, marshaler{
+			typ: reflect.TypeOf((*T)(nil)).Elem(),
+			fn:  func(v any) any { return fn(v.(T)) },
+		})
+	}
+}
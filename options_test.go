@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBuildInfo(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithBuildInfo(),
+		WithVersion("v1.2.3"),
+		With("key1", "value1"),
+	)
+
+	l.Info("foo")
+	require.Contains(t, buf.String(), "go_version="+runtime.Version())
+	require.Contains(t, buf.String(), "version=v1.2.3")
+	require.Contains(t, buf.String(), "key1=value1")
+}
+
+func TestWithHostInfo(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithHostInfo(),
+		WithPlatformInfo(),
+	)
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	l.Info("foo")
+	require.Contains(t, buf.String(), "hostname="+hostname)
+	require.Contains(t, buf.String(), "pid="+strconv.Itoa(os.Getpid()))
+	require.Contains(t, buf.String(), "goos="+runtime.GOOS)
+	require.Contains(t, buf.String(), "goarch="+runtime.GOARCH)
+}
+
+func TestWithContextExtractor(t *testing.T) {
+	var buf bytes.Buffer
+	type ctxKey struct{}
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("debug"),
+		WithContextExtractor(func(ctx context.Context) []slog.Attr {
+			if id, ok := ctx.Value(ctxKey{}).(string); ok {
+				return []slog.Attr{slog.String("request_id", id)}
+			}
+			return nil
+		}),
+	)
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "abc123")
+
+	tests := []struct {
+		level string
+		fn    func(ctx context.Context, msg any, keyvals ...any)
+	}{
+		{"debug", l.DebugCtx},
+		{"info", l.InfoCtx},
+		{"warn", l.WarnCtx},
+		{"err", l.ErrCtx},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			defer buf.Reset()
+
+			tt.fn(ctx, "foo", "key1", "value1")
+			require.Contains(t, buf.String(), "level="+tt.level)
+			require.Contains(t, buf.String(), "request_id=abc123")
+			require.Contains(t, buf.String(), "key1=value1")
+			require.Contains(t, buf.String(), "caller=github.com/jasonhancock/go-logger/options_test.go")
+		})
+	}
+
+	t.Run("no value", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.Info("foo")
+		require.NotContains(t, buf.String(), "request_id")
+	})
+}
+
+func TestBuildInfoAttrs(t *testing.T) {
+	attrs := buildInfoAttrs(&debug.BuildInfo{
+		GoVersion: "go1.21.0",
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "0123456789abcdef0123456789abcdef01234567"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	})
+
+	require.Equal(t, []slog.Attr{
+		slog.String("go_version", "go1.21.0"),
+		slog.String("vcs_revision", "0123456789ab"),
+		slog.Bool("vcs_dirty", true),
+	}, attrs)
+}
+
+func TestWithTimeLocationName(t *testing.T) {
+	ts := time.Date(2023, 4, 13, 17, 38, 13, 0, time.UTC)
+
+	t.Run("valid", func(t *testing.T) {
+		var buf bytes.Buffer
+		l, err := NewE(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithTimeLocationName("America/Denver"),
+			WithTimeFormat(time.RFC3339),
+			WithClock(func() time.Time { return ts }),
+		)
+		if err != nil {
+			t.Skipf("time zone database unavailable: %v", err)
+		}
+
+		l.Info("foo")
+		require.Contains(t, buf.String(), "ts=2023-04-13T11:38:13-06:00 ")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewE(WithDestination(io.Discard), WithTimeLocationName("Mars/Olympus_Mons"))
+		require.ErrorContains(t, err, "logger: loading time location: unknown time zone Mars/Olympus_Mons")
+
+		// New falls back to UTC.
+		var buf bytes.Buffer
+		l := New(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithTimeLocationName("Mars/Olympus_Mons"),
+			WithTimeFormat(time.RFC3339),
+			WithClock(func() time.Time { return ts }),
+		)
+		l.Info("foo")
+		require.Contains(t, buf.String(), "ts=2023-04-13T17:38:13Z ")
+	})
+
+	t.Run("local", func(t *testing.T) {
+		var o options
+		WithLocalTime()(&o)
+		require.Same(t, time.Local, o.timeLocation)
+	})
+}
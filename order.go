@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// isLogfmt reports whether format is written by the text handler.
+func isLogfmt(format string) bool {
+	switch strings.ToLower(format) {
+	case FormatJSON, FormatJSONPretty, FormatMsgpack, FormatCloudEvents:
+		return false
+	}
+	return true
+}
+
+// keyOrderWriter moves the attributes named in order to the front of each
+// logfmt record written to it, in that order, before writing it to w. Other
+// attributes keep their relative order.
+type keyOrderWriter struct {
+	w     io.Writer
+	order []string
+}
+
+func (w *keyOrderWriter) Write(p []byte) (int, error) {
+	line := bytes.TrimSuffix(p, []byte("\n"))
+	fields := splitLogfmt(line)
+	if fields == nil {
+		return w.w.Write(p)
+	}
+
+	out := make([]byte, 0, len(p))
+	used := make([]bool, len(fields))
+	add := func(f []byte) {
+		if len(out) > 0 {
+			out = append(out, ' ')
+		}
+		out = append(out, f...)
+	}
+
+	for _, key := range w.order {
+		for i, f := range fields {
+			if !used[i] && logfmtKey(f) == key {
+				add(f)
+				used[i] = true
+			}
+		}
+	}
+	for i, f := range fields {
+		if !used[i] {
+			add(f)
+		}
+	}
+	out = append(out, '\n')
+
+	if _, err := w.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// splitLogfmt splits a logfmt line into its key=value fields, or returns nil if
+// the line isn't valid logfmt.
+func splitLogfmt(line []byte) [][]byte {
+	var fields [][]byte
+	for i := 0; i < len(line); {
+		start := i
+		var ok bool
+		if i, ok = scanLogfmtToken(line, i, '='); !ok || i >= len(line) || line[i] != '=' {
+			return nil
+		}
+		if i, ok = scanLogfmtToken(line, i+1, ' '); !ok {
+			return nil
+		}
+		fields = append(fields, line[start:i])
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+	}
+	return fields
+}
+
+// scanLogfmtToken returns the index just past the token starting at i, which is
+// either quoted or ends at stop or a space.
+func scanLogfmtToken(line []byte, i int, stop byte) (int, bool) {
+	if i < len(line) && line[i] == '"' {
+		for i++; i < len(line); i++ {
+			switch line[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1, true
+			}
+		}
+		return i, false
+	}
+
+	for i < len(line) && line[i] != stop && line[i] != ' ' {
+		i++
+	}
+	return i, true
+}
+
+// logfmtKey returns the unquoted key of a key=value field.
+func logfmtKey(field []byte) string {
+	end, _ := scanLogfmtToken(field, 0, '=')
+	key := field[:end]
+	if len(key) >= 2 && key[0] == '"' {
+		return string(key[1 : len(key)-1])
+	}
+	return string(key)
+}
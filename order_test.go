@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerKeyOrder(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithCaller(false),
+		WithClock(func() time.Time { return time.Unix(0, 0) }),
+		WithKeyOrder("level", "ts", "msg", "user id"),
+	)
+
+	l.Info("hello world", "a", `x "y" z`, "user id", 42)
+	require.NoError(t, l.Audit("login", "alice", "app"))
+
+	require.Equal(t, `level=info ts=1970-01-01T00:00:00.000Z msg="hello world" "user id"=42 src=go-logger.test a="x \"y\" z"
+level=audit ts=1970-01-01T00:00:00.000Z msg=login src=go-logger.test actor=alice target=app
+`, buf.String())
+}
+
+func TestSplitLogfmt(t *testing.T) {
+	require.Equal(t, [][]byte{[]byte("a=1"), []byte(`b="x y"`), []byte("c=")}, splitLogfmt([]byte(`a=1 b="x y" c=`)))
+	require.Nil(t, splitLogfmt([]byte(`not logfmt`)))
+	require.Nil(t, splitLogfmt([]byte(`a="unterminated`)))
+}
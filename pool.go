@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which buffers aren't returned to their
+// pool, so that an occasional huge record doesn't pin its memory.
+const maxPooledBuffer = 64 << 10
+
+var bufPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBuffer {
+		return
+	}
+	b.Reset()
+	bufPool.Put(b)
+}
+
+var attrsPool = sync.Pool{
+	New: func() any {
+		s := make([]slog.Attr, 0, 16)
+		return &s
+	},
+}
+
+func getAttrs() *[]slog.Attr {
+	return attrsPool.Get().(*[]slog.Attr)
+}
+
+func putAttrs(s *[]slog.Attr) {
+	if cap(*s) > 256 {
+		return
+	}
+	clear(*s)
+	*s = (*s)[:0]
+	attrsPool.Put(s)
+}
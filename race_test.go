@@ -0,0 +1,7 @@
+//go:build race
+
+package logger
+
+// raceEnabled reports whether the race detector is enabled. It randomly drops
+// pooled values, so allocation counts are meaningless under it.
+const raceEnabled = true
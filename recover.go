@@ -0,0 +1,24 @@
+package logger
+
+import "runtime/debug"
+
+// Recover recovers from a panic and logs the panic value and the stack of the
+// goroutine at the error level. It must be deferred directly:
+//
+//	go func() {
+//		defer logger.Recover(l)
+//		...
+//	}()
+//
+// If the logger was created with WithRepanic, the panic continues after it is
+// logged.
+func Recover(l *L) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	l.Err("panic in goroutine", "panic", r, "stack", string(debug.Stack()))
+	if l != nil && l.repanic {
+		panic(r)
+	}
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecover(t *testing.T) {
+	t.Run("logs", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false))
+
+		func() {
+			defer Recover(l)
+			panic("boom")
+		}()
+		require.Regexp(t, `level=err msg="panic in goroutine" src=go-logger.test panic=boom stack=".*recover_test.go`, buf.String())
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(WithDestination(&buf), WithLevel("info"))
+
+		func() {
+			defer Recover(l)
+		}()
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("repanic", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(WithDestination(&buf), WithLevel("info"), WithRepanic())
+
+		require.PanicsWithValue(t, "boom", func() {
+			defer Recover(l)
+			panic("boom")
+		})
+		require.Contains(t, buf.String(), "panic=boom")
+	})
+}
@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry tracks the loggers created with (*L).New from loggers using it and
+// controls their levels at runtime. Loggers are identified by their src chain
+// below the application name, e.g. db.conn; the root logger is the empty name.
+// Each logger's level is the level set on the nearest of itself and its
+// ancestors, like a logback logger tree.
+type Registry struct {
+	mu    sync.RWMutex
+	nodes map[string]*registryNode
+}
+
+type registryNode struct {
+	level    slog.LevelVar
+	explicit *slog.Level
+}
+
+// LoggerInfo describes a logger known to a Registry.
+type LoggerInfo struct {
+	Name string `json:"name"`
+	// Level is the logger's effective level.
+	Level string `json:"level"`
+	// Explicit is true if the level was set on this logger rather than inherited.
+	Explicit bool `json:"explicit"`
+}
+
+// NewRegistry returns an empty Registry. Pass it to WithRegistry.
+func NewRegistry() *Registry {
+	return &Registry{nodes: make(map[string]*registryNode)}
+}
+
+// levelVar returns the level of the named logger, registering it if needed.
+func (r *Registry) levelVar(name string) *slog.LevelVar {
+	r.mu.RLock()
+	n, ok := r.nodes[name]
+	r.mu.RUnlock()
+	if ok {
+		return &n.level
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n, ok := r.nodes[name]; ok {
+		return &n.level
+	}
+	n = &registryNode{}
+	n.level.Set(r.inherited(name))
+	r.nodes[name] = n
+	return &n.level
+}
+
+// rootLevel returns the root logger's level, setting it to lvl unless a level
+// was already set.
+func (r *Registry) rootLevel(lvl slog.Level) *slog.LevelVar {
+	r.mu.Lock()
+	n, ok := r.nodes[""]
+	if !ok || n.explicit == nil {
+		if !ok {
+			n = &registryNode{}
+			r.nodes[""] = n
+		}
+		n.explicit = &lvl
+		r.propagate()
+	}
+	r.mu.Unlock()
+	return &n.level
+}
+
+// SetLevel sets the level of the named logger and every descendant that
+// doesn't have a level of its own. The logger doesn't need to exist yet.
+func (r *Registry) SetLevel(name, level string) {
+	lvl := ParseLevel(level).Level()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.nodes[name]
+	if !ok {
+		n = &registryNode{}
+		r.nodes[name] = n
+	}
+	n.explicit = &lvl
+	r.propagate()
+}
+
+// swapLevel sets or, if lvl is nil, removes the level set on the named logger,
+// returning the level previously set on it.
+func (r *Registry) swapLevel(name string, lvl *slog.Level) *slog.Level {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.nodes[name]
+	if !ok {
+		n = &registryNode{}
+		r.nodes[name] = n
+	}
+	prev := n.explicit
+	n.explicit = lvl
+	r.propagate()
+	return prev
+}
+
+// ResetLevel removes the level set on the named logger so it inherits from its
+// ancestors again. The root logger's level can't be reset.
+func (r *Registry) ResetLevel(name string) {
+	if name == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n, ok := r.nodes[name]; ok {
+		n.explicit = nil
+		r.propagate()
+	}
+}
+
+// Level returns the effective level of the named logger.
+func (r *Registry) Level(name string) slog.Level {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if n, ok := r.nodes[name]; ok {
+		return n.level.Level()
+	}
+	return r.inherited(name)
+}
+
+// Loggers lists the known loggers sorted by name.
+func (r *Registry) Loggers() []LoggerInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]LoggerInfo, 0, len(r.nodes))
+	for name, n := range r.nodes {
+		out = append(out, LoggerInfo{
+			Name:     name,
+			Level:    levelLabel(n.level.Level()),
+			Explicit: n.explicit != nil,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// inherited returns the level set on the nearest ancestor of name, or on name
+// itself. r.mu must be held.
+func (r *Registry) inherited(name string) slog.Level {
+	for {
+		if n, ok := r.nodes[name]; ok && n.explicit != nil {
+			return *n.explicit
+		}
+		if name == "" {
+			return LevelAll
+		}
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[:i]
+		} else {
+			name = ""
+		}
+	}
+}
+
+// propagate recomputes the effective level of every logger. r.mu must be held.
+func (r *Registry) propagate() {
+	for name, n := range r.nodes {
+		n.level.Set(r.inherited(name))
+	}
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerRegistry(t *testing.T) {
+	var buf bytes.Buffer
+	reg := NewRegistry()
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithName("app"),
+		WithCaller(false),
+		WithRegistry(reg),
+	)
+
+	db := l.New("db")
+	conn := db.New("conn")
+	http := l.New("http")
+
+	conn.Debug("conn debug 1")
+	reg.SetLevel("db", "debug")
+	conn.Debug("conn debug 2")
+	http.Debug("http debug")
+	l.Debug("root debug")
+
+	reg.SetLevel("db.conn", "warn")
+	conn.Info("conn info")
+	db.Debug("db debug")
+
+	// Loggers created after a level was set pick it up.
+	db.New("tx").Debug("tx debug")
+
+	reg.ResetLevel("db.conn")
+	conn.Debug("conn debug 3")
+
+	out := buf.String()
+	require.NotContains(t, out, "conn debug 1")
+	require.Contains(t, out, "conn debug 2")
+	require.NotContains(t, out, "http debug")
+	require.NotContains(t, out, "root debug")
+	require.NotContains(t, out, "conn info")
+	require.Contains(t, out, "db debug")
+	require.Contains(t, out, "tx debug")
+	require.Contains(t, out, "conn debug 3")
+
+	require.Equal(t, []LoggerInfo{
+		{Name: "", Level: "info", Explicit: true},
+		{Name: "db", Level: "debug", Explicit: true},
+		{Name: "db.conn", Level: "debug"},
+		{Name: "db.tx", Level: "debug"},
+		{Name: "http", Level: "info"},
+	}, reg.Loggers())
+	require.Equal(t, slog.LevelDebug, reg.Level("db.unknown"))
+
+	reg.SetLevel("", "err")
+	require.Equal(t, slog.LevelError, reg.Level("http"))
+	require.Equal(t, slog.LevelDebug, reg.Level("db"))
+}
@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type replaceAttrFunc = func(groups []string, a slog.Attr) slog.Attr
+
+// chainReplaceAttr returns a ReplaceAttr function that calls each of the non-nil
+// fns in order, stopping once an attribute has been dropped.
+func chainReplaceAttr(fns ...replaceAttrFunc) replaceAttrFunc {
+	var chain []replaceAttrFunc
+	for _, fn := range fns {
+		if fn != nil {
+			chain = append(chain, fn)
+		}
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		for _, fn := range chain {
+			if a = fn(groups, a); a.Key == "" {
+				break
+			}
+		}
+		return a
+	}
+}
+
+// marshaler converts values of type typ before they are logged.
+type marshaler struct {
+	typ reflect.Type
+	fn  func(any) any
+}
+
+// marshalValues returns a ReplaceAttr function that converts values using the
+// first matching marshaler, or nil if there are none.
+func marshalValues(marshalers []marshaler) replaceAttrFunc {
+	if len(marshalers) == 0 {
+		return nil
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() == slog.KindGroup {
+			return a
+		}
+
+		v := a.Value.Any()
+		if v == nil {
+			return a
+		}
+
+		t := reflect.TypeOf(v)
+		for _, m := range marshalers {
+			if t == m.typ || (m.typ.Kind() == reflect.Interface && t.Implements(m.typ)) {
+				a.Value = slog.AnyValue(m.fn(v))
+				break
+			}
+		}
+		return a
+	}
+}
+
+// truncateValues returns a ReplaceAttr function that truncates string values
+// longer than n bytes, or nil if n is not positive.
+func truncateValues(n int) replaceAttrFunc {
+	if n <= 0 {
+		return nil
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() == slog.KindString {
+			a.Value = slog.StringValue(truncate(a.Value.String(), n))
+		}
+		return a
+	}
+}
+
+// truncate shortens s to at most n bytes without splitting a multi-byte
+// character, noting the original length.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	i := n
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+
+	return fmt.Sprintf("%s...(%d bytes)", s[:i], len(s))
+}
+
+// ansiEscape matches ANSI CSI and OSC escape sequences.
+var ansiEscape = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\))`)
+
+// sanitizeValues returns a ReplaceAttr function that sanitizes string values,
+// or nil if enabled is false.
+func sanitizeValues(enabled bool) replaceAttrFunc {
+	if !enabled {
+		return nil
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() == slog.KindString {
+			a.Value = slog.StringValue(sanitize(a.Value.String()))
+		}
+		return a
+	}
+}
+
+// sanitize strips ANSI escape sequences from s and escapes any remaining
+// control characters.
+func sanitize(s string) string {
+	if !strings.ContainsFunc(s, isControl) {
+		return s
+	}
+
+	s = ansiEscape.ReplaceAllString(s, "")
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case isControl(r):
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0)
+}
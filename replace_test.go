@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		desc     string
+		input    string
+		n        int
+		expected string
+	}{
+		{"short", "abc", 5, "abc"},
+		{"exact", "abcde", 5, "abcde"},
+		{"long", "abcdefgh", 5, "abcde...(8 bytes)"},
+		{"multibyte boundary", "aé€b", 3, "aé...(7 bytes)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			require.Equal(t, tt.expected, truncate(tt.input, tt.n))
+		})
+	}
+}
+
+func TestLoggerMaxValueLength(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithMaxValueLength(10),
+	)
+
+	l.Info("foo", "payload", strings.Repeat("x", 100), "count", 1234567890123)
+	require.Contains(t, buf.String(), `payload="xxxxxxxxxx...(100 bytes)"`)
+	require.Contains(t, buf.String(), "count=1234567890123")
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		desc     string
+		input    string
+		expected string
+	}{
+		{"clean", "hello world", "hello world"},
+		{"newline", "line1\nts=forged level=err", `line1\nts=forged level=err`},
+		{"carriage return", "a\r\nb", `a\r\nb`},
+		{"tab", "a\tb", `a\tb`},
+		{"ansi color", "\x1b[31mred\x1b[0m", "red"},
+		{"ansi osc", "\x1b]0;title\x07text", "text"},
+		{"other control", "a\x00b\x7f", `a\x00b\x7f`},
+		{"unicode", "héllo €", "héllo €"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			require.Equal(t, tt.expected, sanitize(tt.input))
+		})
+	}
+}
+
+func TestLoggerSanitize(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithFormat(FormatJSON),
+		WithSanitize(),
+	)
+
+	l.Info("foo\nbar", "key1", "\x1b[31mvalue1\x1b[0m\r\n")
+	require.Contains(t, buf.String(), `"msg":"foo\\nbar"`)
+	require.Contains(t, buf.String(), `"key1":"value1\\r\\n"`)
+}
+
+func TestLoggerMarshaler(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithFormat(FormatJSON),
+		WithMarshaler(func(d time.Duration) any { return d.Milliseconds() }),
+		WithMarshaler(func(ip net.IP) any { return ip.String() }),
+		WithMarshaler(func(s fmt.Stringer) any { return "stringer:" + s.String() }),
+	)
+
+	l.Info("foo",
+		"elapsed", 1500*time.Millisecond,
+		"ip", net.ParseIP("10.0.0.1"),
+		"stringer", &myStringer{},
+		"count", 5,
+	)
+	require.Contains(t, buf.String(), `"elapsed":1500`)
+	require.Contains(t, buf.String(), `"ip":"10.0.0.1"`)
+	require.Contains(t, buf.String(), `"stringer":"stringer:my string"`)
+	require.Contains(t, buf.String(), `"count":5`)
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// StartRuntimeStats logs a summary of the Go runtime's memory and goroutine
+// statistics at the info level every interval until ctx is canceled. Sizes are
+// in bytes.
+func StartRuntimeStats(ctx context.Context, l *L, interval time.Duration) {
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				logRuntimeStats(ctx, l)
+			}
+		}
+	}()
+}
+
+func logRuntimeStats(ctx context.Context, l *L) {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	var lastPause time.Duration
+	if m.NumGC > 0 {
+		lastPause = time.Duration(m.PauseNs[(m.NumGC+255)%256])
+	}
+
+	l.InfoCtx(ctx, "runtime stats",
+		"goroutines", runtime.NumGoroutine(),
+		"heap_alloc", m.HeapAlloc,
+		"heap_inuse", m.HeapInuse,
+		"heap_objects", m.HeapObjects,
+		"sys", m.Sys,
+		"num_gc", m.NumGC,
+		"gc_pause_total", time.Duration(m.PauseTotalNs),
+		"gc_pause_last", lastPause,
+	)
+}
@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartRuntimeStats(t *testing.T) {
+	var buf syncBuffer
+	l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	StartRuntimeStats(ctx, l, 5*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), `msg="runtime stats"`)
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+
+	require.Regexp(t, `msg="runtime stats" src=go-logger.test goroutines=\d+ heap_alloc=\d+ heap_inuse=\d+ heap_objects=\d+ sys=\d+ num_gc=\d+ gc_pause_total=\S+ gc_pause_last=\S+\n`, buf.String())
+}
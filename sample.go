@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// levelSampler keeps a fixed fraction of the records at each sampled level. The
+// rates can be changed while records are being logged.
+type levelSampler struct {
+	levels atomic.Pointer[map[slog.Level]*sampleRate]
+}
+
+// sampleRate counts the records seen by a sampler keeping a fraction of them.
+type sampleRate struct {
+	rate float64
+	seen atomic.Uint64
+}
+
+func newSampleRate(rate float64) *sampleRate {
+	return &sampleRate{rate: min(max(rate, 0), 1)}
+}
+
+// keep reports whether the next record should be kept. Records are kept evenly
+// rather than randomly: with a rate of 0.25, every fourth record is.
+func (r *sampleRate) keep() bool {
+	n := r.seen.Add(1)
+	if r.rate == 1 {
+		return true
+	}
+
+	// Keep the nth record if the number of records that should have been kept
+	// goes up with it.
+	return uint64(float64(n)*r.rate) != uint64(float64(n-1)*r.rate)
+}
+
+func newLevelSampler(rates map[string]float64) *levelSampler {
+	s := &levelSampler{}
+	s.set(rates)
+	return s
+}
+
+// set replaces the rates of the levels in rates. A negative rate removes the
+// level's rate so that all of its records are kept.
+func (s *levelSampler) set(rates map[string]float64) {
+	levels := make(map[slog.Level]*sampleRate)
+	if old := s.levels.Load(); old != nil {
+		for lvl, sr := range *old {
+			levels[lvl] = sr
+		}
+	}
+	for name, rate := range rates {
+		lvl := ParseLevel(name).Level()
+		if rate < 0 {
+			delete(levels, lvl)
+			continue
+		}
+		levels[lvl] = newSampleRate(rate)
+	}
+	s.levels.Store(&levels)
+}
+
+// sample reports whether a record at lvl should be kept.
+func (s *levelSampler) sample(lvl slog.Level) bool {
+	sr, ok := (*s.levels.Load())[lvl]
+	return !ok || sr.keep()
+}
+
+// SampleStats describe the sampling of the records at one level.
+type SampleStats struct {
+	// Rate is the fraction of records kept.
+	Rate float64 `json:"rate"`
+	// Seen is the number of records sampled since the rate was set.
+	Seen uint64 `json:"seen"`
+	// Kept is the number of those records that were kept.
+	Kept uint64 `json:"kept"`
+}
+
+// stats returns the sampling of each sampled level, by level name.
+func (s *levelSampler) stats() map[string]SampleStats {
+	levels := *s.levels.Load()
+	if len(levels) == 0 {
+		return nil
+	}
+
+	st := make(map[string]SampleStats, len(levels))
+	for lvl, sr := range levels {
+		seen := sr.seen.Load()
+		st[levelLabel(lvl)] = SampleStats{Rate: sr.rate, Seen: seen, Kept: uint64(float64(seen) * sr.rate)}
+	}
+	return st
+}
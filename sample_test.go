@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelSampler(t *testing.T) {
+	tests := []struct {
+		rate float64
+		kept int
+	}{
+		{0, 0},
+		{0.01, 10},
+		{0.1, 100},
+		{0.25, 250},
+		{1.0 / 3, 333},
+		{1, 1000},
+		{2, 1000},
+	}
+
+	for _, tt := range tests {
+		s := newLevelSampler(map[string]float64{"debug": tt.rate})
+		var kept int
+		for i := 0; i < 1000; i++ {
+			if s.sample(slog.LevelDebug) {
+				kept++
+			}
+			require.True(t, s.sample(slog.LevelInfo))
+		}
+		require.Equal(t, tt.kept, kept, "rate %v", tt.rate)
+	}
+
+	s := newLevelSampler(nil)
+	require.True(t, s.sample(slog.LevelDebug))
+	require.Nil(t, s.stats())
+
+	s.set(map[string]float64{"debug": 0.5, "info": 0})
+	for i := 0; i < 10; i++ {
+		s.sample(slog.LevelDebug)
+	}
+	require.Equal(t, map[string]SampleStats{
+		"debug": {Rate: 0.5, Seen: 10, Kept: 5},
+		"info":  {Rate: 0},
+	}, s.stats())
+
+	s.set(map[string]float64{"info": -1})
+	require.True(t, s.sample(slog.LevelInfo))
+	require.Equal(t, map[string]SampleStats{"debug": {Rate: 0.5, Seen: 10, Kept: 5}}, s.stats())
+}
+
+func TestLoggerSampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("debug"),
+		WithCaller(false),
+		WithSampling(map[string]float64{"debug": 0, "info": 0.5}),
+	)
+
+	sub := l.New("sub")
+	for i := 0; i < 5; i++ {
+		l.Debug("debug")
+		l.Info("info")
+		sub.Info("info")
+		l.Warn("warn")
+	}
+
+	out := buf.String()
+	require.Zero(t, strings.Count(out, "msg=debug"))
+	require.Equal(t, 5, strings.Count(out, "msg=info"))
+	require.Equal(t, 5, strings.Count(out, "msg=warn"))
+	require.Equal(t, uint64(10), l.Stats().Dropped)
+
+	_, err := NewE(WithDestination(&buf), WithSampling(map[string]float64{"loud": 0.5, "info": 1.5}))
+	require.ErrorContains(t, err, `logger: unknown sampling level "loud"`)
+	require.ErrorContains(t, err, `logger: sampling rate 1.5 for level "info" is not between 0 and 1`)
+}
@@ -0,0 +1,342 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sentryMaxTagLength is the longest value Sentry accepts for a tag. Longer
+// string values are sent as extra data instead.
+const sentryMaxTagLength = 200
+
+// sentryQueueSize is the number of events buffered for sending. Events are
+// dropped when the queue is full so that logging never blocks on Sentry.
+const sentryQueueSize = 100
+
+var (
+	errSentryQueueFull = errors.New("sentry: queue full, event dropped")
+	errSentryClosed    = errors.New("sentry: closed, event dropped")
+)
+
+// sentryClient sends events to the Sentry envelope endpoint in the background.
+type sentryClient struct {
+	dsn      string
+	endpoint string
+	auth     string
+	client   *http.Client
+	onError  func(error)
+
+	mu     sync.Mutex
+	closed bool
+	queue  chan []byte
+	wg     sync.WaitGroup
+}
+
+// newSentryClient parses a DSN of the form
+// https://<public key>@<host>/<project id> and starts the sender.
+func newSentryClient(dsn string, onError func(error)) (*sentryClient, error) {
+	endpoint, key, err := parseSentryDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &sentryClient{
+		dsn:      dsn,
+		endpoint: endpoint,
+		auth:     "Sentry sentry_version=7, sentry_client=go-logger, sentry_key=" + key,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		onError:  onError,
+		queue:    make(chan []byte, sentryQueueSize),
+	}
+
+	c.wg.Add(1)
+	go c.run()
+	return c, nil
+}
+
+// parseSentryDSN returns the envelope endpoint and public key of a DSN.
+func parseSentryDSN(dsn string) (endpoint, key string, err error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("parsing sentry dsn: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", "", errors.New("parsing sentry dsn: missing public key")
+	}
+	dir, project := path.Split(strings.TrimSuffix(u.Path, "/"))
+	if project == "" {
+		return "", "", errors.New("parsing sentry dsn: missing project id")
+	}
+	return u.Scheme + "://" + u.Host + dir + "api/" + project + "/envelope/", u.User.Username(), nil
+}
+
+// enqueue queues envelope for sending, dropping it if the queue is full or the
+// client is closed, since records can still be logged after Close.
+func (c *sentryClient) enqueue(envelope []byte) {
+	c.mu.Lock()
+	err := errSentryClosed
+	if !c.closed {
+		select {
+		case c.queue <- envelope:
+			err = nil
+		default:
+			err = errSentryQueueFull
+		}
+	}
+	c.mu.Unlock()
+	c.report(err)
+}
+
+func (c *sentryClient) run() {
+	defer c.wg.Done()
+	for envelope := range c.queue {
+		c.report(c.send(envelope))
+	}
+}
+
+func (c *sentryClient) send(envelope []byte) error {
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(envelope))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", c.auth)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("sentry: %s", resp.Status)
+	}
+	return nil
+}
+
+func (c *sentryClient) report(err error) {
+	if err != nil && c.onError != nil {
+		c.onError(err)
+	}
+}
+
+// Close waits for queued events to be sent. Events enqueued afterwards are
+// dropped.
+func (c *sentryClient) Close() error {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.queue)
+	}
+	c.mu.Unlock()
+	c.wg.Wait()
+	return nil
+}
+
+// sentryHandler forwards error and fatal records to Sentry in addition to
+// passing them to the inner handler. String attributes short enough to be tags
+// become tags; everything else is sent as extra data. Attributes in groups are
+// flattened into dotted keys.
+type sentryHandler struct {
+	inner  slog.Handler
+	client *sentryClient
+	keys   KeyNames
+	prefix string
+	attrs  []slog.Attr
+}
+
+func (h *sentryHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.inner.Enabled(ctx, lvl)
+}
+
+func (h *sentryHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelError {
+		h.client.enqueue(h.envelope(r))
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *sentryHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.inner = h.inner.WithAttrs(attrs)
+	c.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], prefixAttrs(h.prefix, attrs)...)
+	return &c
+}
+
+func (h *sentryHandler) WithGroup(name string) slog.Handler {
+	c := *h
+	c.inner = h.inner.WithGroup(name)
+	c.prefix = h.prefix + name + "."
+	return &c
+}
+
+func prefixAttrs(prefix string, attrs []slog.Attr) []slog.Attr {
+	if prefix == "" {
+		return attrs
+	}
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = slog.Attr{Key: prefix + a.Key, Value: a.Value}
+	}
+	return out
+}
+
+// sentryFrame is a stack frame in the Sentry event format.
+type sentryFrame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+}
+
+// envelope builds a Sentry envelope holding a single event for r.
+func (h *sentryHandler) envelope(r slog.Record) []byte {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	eventID := hex.EncodeToString(id[:])
+
+	level := "error"
+	if r.Level >= LevelFatal {
+		level = "fatal"
+	}
+
+	tags := map[string]string{}
+	extra := map[string]any{}
+	add := func(a slog.Attr) {
+		addSentryAttr(tags, extra, "", a)
+	}
+	for _, a := range h.attrs {
+		add(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		add(slog.Attr{Key: h.prefix + a.Key, Value: a.Value})
+		return true
+	})
+
+	event := map[string]any{
+		"event_id":  eventID,
+		"timestamp": r.Time.UTC().Format(time.RFC3339Nano),
+		"level":     level,
+		"platform":  "go",
+		"message":   r.Message,
+		"threads": map[string]any{
+			"values": []any{map[string]any{
+				"current":    true,
+				"stacktrace": map[string]any{"frames": sentryStack()},
+			}},
+		},
+	}
+	if src, ok := tags[h.keys.Source]; ok {
+		event["logger"] = src
+		delete(tags, h.keys.Source)
+	}
+	if len(tags) > 0 {
+		event["tags"] = tags
+	}
+	if len(extra) > 0 {
+		event["extra"] = extra
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		// Values that can't be marshaled are dropped rather than losing the event.
+		delete(event, "extra")
+		body, _ = json.Marshal(event)
+	}
+
+	var buf bytes.Buffer
+	hdr, _ := json.Marshal(map[string]string{"event_id": eventID, "dsn": h.client.dsn})
+	buf.Write(hdr)
+	buf.WriteString("\n")
+	item, _ := json.Marshal(map[string]any{"type": "event", "length": len(body)})
+	buf.Write(item)
+	buf.WriteString("\n")
+	buf.Write(body)
+	buf.WriteString("\n")
+	return buf.Bytes()
+}
+
+func addSentryAttr(tags map[string]string, extra map[string]any, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	key := prefix + a.Key
+
+	switch v.Kind() {
+	case slog.KindGroup:
+		if a.Key != "" {
+			prefix = key + "."
+		}
+		for _, ga := range v.Group() {
+			addSentryAttr(tags, extra, prefix, ga)
+		}
+	case slog.KindString:
+		if s := v.String(); len(s) <= sentryMaxTagLength {
+			tags[key] = s
+		} else {
+			extra[key] = s
+		}
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			extra[key] = err.Error()
+			break
+		}
+		extra[key] = v.Any()
+	default:
+		extra[key] = v.Any()
+	}
+}
+
+// sentryStack returns the stack of the code that logged the record, outermost
+// frame first as Sentry expects, skipping frames inside this package and
+// log/slog.
+func sentryStack() []sentryFrame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var out []sentryFrame
+	for {
+		f, more := frames.Next()
+		if !isLoggerFrame(f) {
+			out = append(out, sentryFrame{
+				Function: f.Function,
+				Module:   funcPackage(f.Function),
+				Filename: path.Base(f.File),
+				AbsPath:  f.File,
+				Lineno:   f.Line,
+			})
+		}
+		if !more {
+			break
+		}
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+const loggerPkg = "github.com/jasonhancock/go-logger."
+
+func isLoggerFrame(f runtime.Frame) bool {
+	if strings.HasSuffix(f.File, "_test.go") {
+		return false
+	}
+	return strings.HasPrefix(f.Function, loggerPkg) || strings.HasPrefix(f.Function, "log/slog.")
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerSentry(t *testing.T) {
+	var mu sync.Mutex
+	var events []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/prefix/api/42/envelope/", r.URL.Path)
+		require.Contains(t, r.Header.Get("X-Sentry-Auth"), "sentry_key=pub")
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		sc := bufio.NewScanner(bytes.NewReader(body))
+		sc.Buffer(nil, 1<<20)
+		var lines []string
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+		require.Len(t, lines, 3)
+		require.Contains(t, lines[1], `"type":"event"`)
+
+		var ev map[string]any
+		require.NoError(t, json.Unmarshal([]byte(lines[2]), &ev))
+		mu.Lock()
+		events = append(events, ev)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	dsn := strings.Replace(srv.URL, "http://", "http://pub@", 1) + "/prefix/42"
+
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithName("app"),
+		WithSentry(dsn),
+		WithErrorHandler(func(err error) { t.Error(err) }),
+	)
+
+	l.Info("not sent")
+	l.New("db").With("region", "us-east").Err("query failed",
+		"error", errors.New("timeout"),
+		"attempts", 3,
+		"query", strings.Repeat("x", 300),
+		slog.Group("req", "id", "abc"),
+	)
+	require.NoError(t, l.Close())
+
+	require.Contains(t, buf.String(), "msg=\"query failed\"")
+	require.Len(t, events, 1)
+
+	ev := events[0]
+	require.Equal(t, "query failed", ev["message"])
+	require.Equal(t, "error", ev["level"])
+	require.Equal(t, "app.db", ev["logger"])
+	require.Equal(t, map[string]any{"region": "us-east", "req.id": "abc"}, withoutKey(ev["tags"], "caller"))
+
+	extra := ev["extra"].(map[string]any)
+	require.Equal(t, "timeout", extra["error"])
+	require.Equal(t, float64(3), extra["attempts"])
+	require.Len(t, extra["query"], 300)
+
+	threads := ev["threads"].(map[string]any)["values"].([]any)
+	frames := threads[0].(map[string]any)["stacktrace"].(map[string]any)["frames"].([]any)
+	last := frames[len(frames)-1].(map[string]any)
+	require.Equal(t, "github.com/jasonhancock/go-logger.TestLoggerSentry", last["function"])
+	require.Equal(t, "sentry_test.go", last["filename"])
+}
+
+func TestLoggerSentryInvalidDSN(t *testing.T) {
+	var reported error
+	l := New(
+		WithDestination(io.Discard),
+		WithLevel("info"),
+		WithSentry("https://sentry.example.com/42"),
+		WithErrorHandler(func(err error) { reported = err }),
+	)
+	l.Err("foo")
+	require.EqualError(t, reported, "parsing sentry dsn: missing public key")
+}
+
+func TestLoggerSentryAfterClose(t *testing.T) {
+	var mu sync.Mutex
+	var reported []error
+	l := New(
+		WithDestination(io.Discard),
+		WithLevel("info"),
+		WithSentry("http://key@127.0.0.1:1/42"),
+		WithErrorHandler(func(err error) {
+			mu.Lock()
+			reported = append(reported, err)
+			mu.Unlock()
+		}),
+	)
+	require.NoError(t, l.Close())
+
+	l.Err("after close")
+	require.NoError(t, l.Close())
+	require.Equal(t, []error{errSentryClosed}, reported)
+}
+
+func withoutKey(v any, key string) map[string]any {
+	m := v.(map[string]any)
+	delete(m, key)
+	return m
+}
@@ -0,0 +1,326 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Uploader stores a chunk of log output in an object store such as S3 or GCS.
+type Uploader interface {
+	Upload(ctx context.Context, name string, r io.Reader) error
+}
+
+// DefaultShipperNameTemplate is the object name template used when none is set.
+const DefaultShipperNameTemplate = `{{.Host}}/{{.Time.Format "2006/01/02/150405"}}-{{.ID}}.log.gz`
+
+// ShipperConfig configures a Shipper.
+type ShipperConfig struct {
+	// Dir is the local directory records are buffered in. Chunks that have not
+	// been uploaded are picked up again when a Shipper is created on the same
+	// directory, e.g. after a restart.
+	Dir string
+
+	// Interval is how often the current chunk is uploaded. Defaults to one minute.
+	Interval time.Duration
+
+	// MaxSize is the size in bytes at which the current chunk is uploaded without
+	// waiting for the interval. Defaults to 8MB.
+	MaxSize int64
+
+	// NameTemplate is a text/template producing the object name for each chunk.
+	// It is executed with the fields Time, Host, and ID. Defaults to
+	// DefaultShipperNameTemplate.
+	NameTemplate string
+
+	// OnError is called whenever uploading a chunk fails. Failed chunks are kept
+	// and retried on the next interval.
+	OnError func(error)
+}
+
+const (
+	shipperCurrent = "current.log"
+	shipperPending = ".pending"
+)
+
+// Shipper is a destination that buffers log records in a local directory and
+// periodically uploads them as gzip compressed chunks via an Uploader.
+type Shipper struct {
+	cfg  ShipperConfig
+	up   Uploader
+	tmpl *template.Template
+	host string
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+	last int64
+
+	uploadMu sync.Mutex
+	lastErr  error
+	kick     chan struct{}
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewShipper returns a Shipper uploading chunks with u. Any chunks left in the
+// directory by a previous Shipper are uploaded in the background.
+func NewShipper(u Uploader, cfg ShipperConfig) (*Shipper, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("shipper directory is required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = 8 << 20
+	}
+	if cfg.NameTemplate == "" {
+		cfg.NameTemplate = DefaultShipperNameTemplate
+	}
+
+	tmpl, err := template.New("name").Parse(cfg.NameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing name template: %w", err)
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	host, _ := os.Hostname()
+	s := &Shipper{
+		cfg:  cfg,
+		up:   u,
+		tmpl: tmpl,
+		host: host,
+		kick: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	err = s.rotate()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.run()
+	s.trigger()
+
+	return s, nil
+}
+
+// Write appends p to the current chunk.
+func (s *Shipper) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return 0, os.ErrClosed
+	}
+
+	n, err := s.f.Write(p)
+	s.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+
+	if s.size >= s.cfg.MaxSize {
+		if err := s.rotate(); err != nil {
+			return n, err
+		}
+		s.trigger()
+	}
+	return n, nil
+}
+
+// Flush uploads the current chunk and any chunks that previously failed to
+// upload.
+func (s *Shipper) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	err := s.rotate()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return s.upload(ctx)
+}
+
+// Close stops the background uploads and makes a final attempt to upload
+// everything that has been written. Chunks that fail to upload remain in the
+// directory.
+func (s *Shipper) Close() error {
+	s.mu.Lock()
+	if s.f == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	err := s.rotate()
+	if cerr := s.f.Close(); err == nil {
+		err = cerr
+	}
+	s.f = nil
+	s.mu.Unlock()
+
+	close(s.done)
+	s.wg.Wait()
+
+	return errors.Join(err, s.upload(context.Background()))
+}
+
+func (s *Shipper) trigger() {
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Shipper) run() {
+	defer s.wg.Done()
+
+	t := time.NewTicker(s.cfg.Interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-t.C:
+			s.mu.Lock()
+			err := s.rotate()
+			s.mu.Unlock()
+			s.report(err)
+		case <-s.kick:
+		}
+		s.report(s.upload(context.Background()))
+	}
+}
+
+// Health returns the error of the last upload, if it failed, along with the
+// number of chunks waiting to be uploaded.
+func (s *Shipper) Health() error {
+	s.uploadMu.Lock()
+	defer s.uploadMu.Unlock()
+
+	if s.lastErr == nil {
+		return nil
+	}
+	matches, _ := filepath.Glob(filepath.Join(s.cfg.Dir, "*"+shipperPending))
+	return fmt.Errorf("%d chunks pending, last upload failed: %w", len(matches), s.lastErr)
+}
+
+func (s *Shipper) report(err error) {
+	if err != nil && s.cfg.OnError != nil {
+		s.cfg.OnError(err)
+	}
+}
+
+// rotate marks the current chunk as pending and starts a new one. Empty chunks
+// are reused. s.mu must be held.
+func (s *Shipper) rotate() error {
+	cur := filepath.Join(s.cfg.Dir, shipperCurrent)
+
+	if s.f != nil && s.size == 0 {
+		return nil
+	}
+
+	if s.f != nil {
+		if err := s.f.Close(); err != nil {
+			return err
+		}
+		s.f = nil
+	}
+
+	if fi, err := os.Stat(cur); err == nil && fi.Size() > 0 {
+		// Chunk IDs are timestamps, kept unique so that multiple rotations
+		// within the clock's resolution don't collide.
+		id := time.Now().UnixNano()
+		if id <= s.last {
+			id = s.last + 1
+		}
+		s.last = id
+		if err := os.Rename(cur, filepath.Join(s.cfg.Dir, strconv.FormatInt(id, 10)+shipperPending)); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(cur, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	s.f = f
+	s.size = 0
+	return nil
+}
+
+// upload uploads and removes all pending chunks, oldest first.
+func (s *Shipper) upload(ctx context.Context) error {
+	s.uploadMu.Lock()
+	defer s.uploadMu.Unlock()
+
+	matches, err := filepath.Glob(filepath.Join(s.cfg.Dir, "*"+shipperPending))
+	if err != nil {
+		return err
+	}
+	sort.Strings(matches)
+
+	var errs []error
+	for _, path := range matches {
+		if err := s.uploadChunk(ctx, path); err != nil {
+			errs = append(errs, fmt.Errorf("uploading %s: %w", filepath.Base(path), err))
+		}
+	}
+	s.lastErr = errors.Join(errs...)
+	return s.lastErr
+}
+
+func (s *Shipper) uploadChunk(ctx context.Context, path string) error {
+	id := strings.TrimSuffix(filepath.Base(path), shipperPending)
+	nsec, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	var name strings.Builder
+	err = s.tmpl.Execute(&name, struct {
+		Time time.Time
+		Host string
+		ID   string
+	}{time.Unix(0, nsec).UTC(), s.host, id})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := io.Copy(zw, f); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	if err := s.up.Upload(ctx, name.String(), &buf); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memUploader struct {
+	mu      sync.Mutex
+	objects map[string]string
+	err     error
+}
+
+func (u *memUploader) Upload(ctx context.Context, name string, r io.Reader) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.err != nil {
+		return u.err
+	}
+
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return err
+	}
+
+	if u.objects == nil {
+		u.objects = make(map[string]string)
+	}
+	u.objects[name] = string(data)
+	return nil
+}
+
+func (u *memUploader) contents() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var out []string
+	for _, v := range u.objects {
+		out = append(out, v)
+	}
+	return out
+}
+
+func TestShipper(t *testing.T) {
+	dir := t.TempDir()
+	up := &memUploader{}
+
+	s, err := NewShipper(up, ShipperConfig{
+		Dir:          dir,
+		Interval:     time.Hour,
+		NameTemplate: `logs/{{.Time.Format "2006"}}/{{.ID}}.gz`,
+	})
+	require.NoError(t, err)
+
+	l := New(WithDestination(s), WithLevel("info"), WithCaller(false))
+	l.Info("foo")
+	l.Info("bar")
+
+	require.NoError(t, s.Flush(context.Background()))
+	contents := up.contents()
+	require.Len(t, contents, 1)
+	require.Contains(t, contents[0], "msg=foo")
+	require.Contains(t, contents[0], "msg=bar")
+	for name := range up.objects {
+		require.Regexp(t, `^logs/\d{4}/\d+\.gz$`, name)
+	}
+
+	// Flushing an empty chunk uploads nothing.
+	require.NoError(t, s.Flush(context.Background()))
+	require.Len(t, up.contents(), 1)
+
+	l.Info("baz")
+	require.NoError(t, s.Close())
+	require.Len(t, up.contents(), 2)
+
+	_, err = s.Write([]byte("x"))
+	require.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestShipperMaxSize(t *testing.T) {
+	up := &memUploader{}
+
+	s, err := NewShipper(up, ShipperConfig{
+		Dir:      t.TempDir(),
+		Interval: time.Hour,
+		MaxSize:  10,
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	_, err = s.Write([]byte("0123456789\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(up.contents()) == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestShipperResume(t *testing.T) {
+	dir := t.TempDir()
+	up := &memUploader{err: errors.New("unavailable")}
+
+	s, err := NewShipper(up, ShipperConfig{Dir: dir, Interval: time.Hour})
+	require.NoError(t, err)
+
+	_, err = s.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.ErrorContains(t, s.Flush(context.Background()), "unavailable")
+
+	// Simulate a crash leaving data in the current chunk.
+	_, err = s.Write([]byte("second\n"))
+	require.NoError(t, err)
+	s.mu.Lock()
+	s.f.Close()
+	s.f = nil
+	s.mu.Unlock()
+	close(s.done)
+	s.wg.Wait()
+
+	pending, err := filepath.Glob(filepath.Join(dir, "*"+shipperPending))
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	up.mu.Lock()
+	up.err = nil
+	up.mu.Unlock()
+
+	s, err = NewShipper(up, ShipperConfig{Dir: dir, Interval: time.Hour})
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	contents := up.contents()
+	require.Len(t, contents, 2)
+	require.ElementsMatch(t, []string{"first\n", "second\n"}, contents)
+
+	pending, err = filepath.Glob(filepath.Join(dir, "*"+shipperPending))
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
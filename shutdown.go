@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+)
+
+// destination is a writer passed to the logger's options.
+type destination struct {
+	name string
+	w    io.Writer
+}
+
+// destinations returns the writers passed to o, each once.
+func (o *options) destinations() []destination {
+	all := []destination{
+		{"destination", o.destination},
+		{"split destination", o.splitDest},
+		{"fallback destination", o.fallback},
+		{"audit destination", o.auditDestination},
+	}
+	for _, t := range o.tees {
+		all = append(all, destination{"tee", t.w})
+	}
+
+	var dests []destination
+	seen := make(map[any]bool)
+	for _, d := range all {
+		if d.w == nil {
+			continue
+		}
+		// The same writer may be passed more than once.
+		if reflect.TypeOf(d.w).Comparable() {
+			if seen[d.w] {
+				continue
+			}
+			seen[d.w] = true
+		}
+		dests = append(dests, d)
+	}
+	return dests
+}
+
+// flusher is implemented by destinations that buffer records, such as Shipper.
+type flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// Shutdown closes the logger like Close and then finishes delivering records
+// buffered by its destinations: destinations implementing io.Closer, such as
+// Spool, Shipper, and the cloud writers, are closed, except for files such as
+// os.Stdout, and others with a Flush(context.Context) error method are flushed.
+// If ctx is done first, Shutdown returns without waiting for them to finish.
+func (l *L) Shutdown(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		errs := []error{l.Close()}
+		for _, d := range l.dests {
+			var err error
+			switch w := d.w.(type) {
+			case *os.File:
+			case io.Closer:
+				err = w.Close()
+			case flusher:
+				err = w.Flush(ctx)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", d.name, err))
+			}
+		}
+		done <- errors.Join(errs...)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("shutting down logger: %w", ctx.Err())
+	}
+}
+
+// ShutdownOnSignal blocks until the process receives SIGINT or SIGTERM, or ctx
+// is canceled, and then shuts l down, allowing it up to timeout to deliver
+// buffered records. It returns the errors from Shutdown. Typically it is run
+// in its own goroutine, or at the end of main:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	go serve(ctx)
+//	if err := logger.ShutdownOnSignal(ctx, l, 10*time.Second); err != nil {
+//		fmt.Fprintln(os.Stderr, err)
+//	}
+func ShutdownOnSignal(ctx context.Context, l *L, timeout time.Duration) error {
+	sigCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	<-sigCtx.Done()
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return l.Shutdown(shutdownCtx)
+}
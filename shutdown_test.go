@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// flushWriter is a destination with a Flush method.
+type flushWriter struct {
+	syncBuffer
+	flushed bool
+	block   chan struct{}
+}
+
+func (w *flushWriter) Flush(ctx context.Context) error {
+	if w.block != nil {
+		<-w.block
+	}
+	w.flushed = true
+	return nil
+}
+
+func TestLoggerShutdown(t *testing.T) {
+	spool, err := NewSpool(&flakyWriter{}, SpoolConfig{Dir: t.TempDir()})
+	require.NoError(t, err)
+	tee := &flushWriter{}
+
+	l := New(WithDestination(spool), WithTee(tee, FormatJSON), WithFallbackDestination(os.Stderr), WithLevel("info"))
+	l.Info("foo")
+	require.NoError(t, l.Shutdown(context.Background()))
+
+	require.True(t, tee.flushed)
+	_, err = spool.Write([]byte("bar"))
+	require.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestLoggerShutdownTimeout(t *testing.T) {
+	tee := &flushWriter{block: make(chan struct{})}
+	defer close(tee.block)
+	l := New(WithDestination(&syncBuffer{}), WithTee(tee, FormatJSON))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := l.Shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestShutdownOnSignal(t *testing.T) {
+	tee := &flushWriter{}
+	l := New(WithDestination(&syncBuffer{}), WithTee(tee, FormatJSON))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, ShutdownOnSignal(ctx, l, time.Second))
+	require.True(t, tee.flushed)
+}
@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// fullRecord returns a copy of r preceded by the attributes added to the logger.
+func (l *L) fullRecord(r slog.Record) slog.Record {
+	fr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	fr.AddAttrs(l.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		fr.AddAttrs(a)
+		return true
+	})
+	return fr
+}
+
+// sendToSinks passes the full record for r to each record sink.
+func (l *L) sendToSinks(r slog.Record) {
+	if len(l.recordSinks) == 0 {
+		return
+	}
+	fr := l.fullRecord(r)
+	for _, fn := range l.recordSinks {
+		fn(fr)
+	}
+}
+
+// namedSink is a destination, registered with WithNamedSink, that records are
+// sent to only when logged by a logger returned by To.
+type namedSink struct {
+	name   string
+	w      io.Writer
+	format string
+}
+
+// namedSinkHandlers returns a handler for each named sink, keyed by name,
+// sharing opts except for the level, which is left to the logger.
+func (o *options) namedSinkHandlers(opts slog.HandlerOptions, keys KeyNames) map[string]slog.Handler {
+	if len(o.namedSinks) == 0 {
+		return nil
+	}
+
+	opts.Level = LevelAll
+	handlers := make(map[string]slog.Handler, len(o.namedSinks))
+	for _, s := range o.namedSinks {
+		w := s.w
+		if strings.ToLower(s.format) == FormatCloudEvents {
+			w = o.cloudEventsWriter(w, keys)
+		}
+		handlers[s.name] = newFormatHandler(s.format, w, &opts)
+	}
+	return handlers
+}
+
+// To returns a logger that also sends every record it logs to the named sinks
+// registered with WithNamedSink, so that, for example, security relevant
+// records can be copied to a dedicated file:
+//
+//	l.To("security").Warn("login failed", "user", user)
+//
+// Names that weren't registered are ignored.
+func (l *L) To(names ...string) *L {
+	if l == nil {
+		return nil
+	}
+
+	c := l.clone()
+	for _, name := range names {
+		if _, ok := l.namedSinks[name]; ok && !slices.Contains(c.to, name) {
+			c.to = append(c.to, name)
+		}
+	}
+	return c
+}
+
+// Route sends the records matching all of its conditions to a named sink
+// registered with WithNamedSink, in addition to the destination, whichever
+// logger they're logged by. Empty conditions match every record.
+type Route struct {
+	// Sink is the name of the sink.
+	Sink string
+	// Level is the minimum level, as understood by ParseLevel.
+	Level string
+	// Source is a pattern matched against the logger's src chain as by
+	// WithSourceFilter, so db.* matches myapp.db.conn.
+	Source string
+	// Filter is matched against the record as by WithFilter.
+	Filter Filter
+}
+
+// route is a Route ready to be matched against records.
+type route struct {
+	sink   string
+	level  slog.Level
+	source []string
+	filter Filter
+}
+
+// compileRoutes returns the routes to sinks that were registered. Routes to
+// other sinks are reported by validate.
+func (o *options) compileRoutes() []route {
+	var routes []route
+	for _, r := range o.routes {
+		if slices.ContainsFunc(o.namedSinks, func(s namedSink) bool { return s.name == r.Sink }) {
+			routes = append(routes, r.compile())
+		}
+	}
+	return routes
+}
+
+func (r Route) compile() route {
+	c := route{sink: r.Sink, level: LevelAll, filter: r.Filter}
+	if r.Level != "" {
+		c.level = ParseLevel(r.Level).Level()
+	}
+	if r.Source != "" {
+		c.source = []string{r.Source}
+	}
+	return c
+}
+
+// matches reports whether the record fr, logged by a logger with the src chain
+// src, matches the route.
+func (r route) matches(ctx context.Context, fr slog.Record, src []string) bool {
+	if fr.Level < r.level {
+		return false
+	}
+	if r.source != nil && !sourceMatch(r.source, src) {
+		return false
+	}
+	return r.filter == nil || r.filter(ctx, fr)
+}
+
+// sendToNamedSinks passes the full record for r to each named sink the logger
+// was returned by To for, and to each sink with a matching route. Each sink
+// receives the record at most once.
+func (l *L) sendToNamedSinks(ctx context.Context, r slog.Record) {
+	if len(l.to) == 0 && len(l.routes) == 0 {
+		return
+	}
+
+	fr := l.fullRecord(r)
+	for _, name := range l.to {
+		_ = l.namedSinks[name].Handle(ctx, fr.Clone())
+	}
+	var sent []string
+	for _, rt := range l.routes {
+		if slices.Contains(l.to, rt.sink) || slices.Contains(sent, rt.sink) || !rt.matches(ctx, fr, l.src) {
+			continue
+		}
+		sent = append(sent, rt.sink)
+		_ = l.namedSinks[rt.sink].Handle(ctx, fr.Clone())
+	}
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerRecordSink(t *testing.T) {
+	var records []slog.Record
+	l := New(
+		WithDestination(io.Discard),
+		WithLevel("info"),
+		WithName("app"),
+		WithRecordSink(func(r slog.Record) { records = append(records, r.Clone()) }),
+	)
+
+	l.Debug("dropped")
+	l.With("request_id", "abc").Info("foo", "key1", 1)
+	require.NoError(t, l.Audit("login", "alice", "app"))
+
+	require.Len(t, records, 2)
+	require.Equal(t, slog.LevelInfo, records[0].Level)
+	require.Equal(t, "foo", records[0].Message)
+
+	attrs := map[string]slog.Value{}
+	records[0].Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value
+		return true
+	})
+	require.Equal(t, "app", attrs["src"].String())
+	require.Equal(t, "abc", attrs["request_id"].String())
+	require.Equal(t, int64(1), attrs["key1"].Int64())
+	require.Contains(t, attrs["caller"].String(), "sink_test.go:")
+
+	require.Equal(t, LevelAudit, records[1].Level)
+	require.Equal(t, "login", records[1].Message)
+}
+
+func TestLoggerNamedSink(t *testing.T) {
+	var buf, security bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithCaller(false),
+		WithKeyNames(KeyNames{Time: KeyOmit}),
+		WithNamedSink("security", &security, FormatJSON),
+	)
+
+	l.Info("regular")
+	sl := l.With("user", "alice").To("security", "unknown")
+	sl.Debug("dropped")
+	sl.Warn("login failed", "attempts", 3)
+	sl.New("auth").To("security").Info("locked out")
+
+	require.Equal(t, `level=info msg=regular src=go-logger.test
+level=warn msg="login failed" src=go-logger.test user=alice attempts=3
+level=info msg="locked out" src=go-logger.test user=alice src=go-logger.test.auth
+`, buf.String())
+	require.Equal(t, `{"level":"warn","msg":"login failed","src":"go-logger.test","user":"alice","attempts":3}
+{"level":"info","msg":"locked out","src":"go-logger.test","user":"alice","src":"go-logger.test.auth"}
+`, security.String())
+
+	require.Nil(t, Nop().To("security"))
+}
+
+func TestLoggerRoute(t *testing.T) {
+	var errs, payments bytes.Buffer
+	l := New(
+		WithDestination(io.Discard),
+		WithLevel("debug"),
+		WithCaller(false),
+		WithName("app"),
+		WithKeyNames(KeyNames{Time: KeyOmit}),
+		WithNamedSink("errors", &errs, FormatLogFmt),
+		WithNamedSink("payments", &payments, FormatLogFmt),
+		WithRoute(Route{Sink: "errors", Level: "err"}),
+		WithRoute(Route{Sink: "payments", Source: "payments.*"}),
+		WithRoute(Route{Sink: "payments", Filter: HasAttr("order_id")}),
+	)
+
+	pl := l.New("payments").New("stripe")
+	l.Info("started")
+	l.Err("failed")
+	pl.Debug("charging", "order_id", 1)
+	pl.Err("declined")
+	l.Info("refund", "order_id", 2)
+	l.To("errors").Info("forced")
+
+	require.Equal(t, `level=err msg=failed src=app
+level=err msg=declined src=app src=app.payments src=app.payments.stripe
+level=info msg=forced src=app
+`, errs.String())
+	require.Equal(t, `level=debug msg=charging src=app src=app.payments src=app.payments.stripe order_id=1
+level=err msg=declined src=app src=app.payments src=app.payments.stripe
+level=info msg=refund src=app order_id=2
+`, payments.String())
+}
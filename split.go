@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// levelSplit is the destination of loggers created with WithLevelSplit. Writes
+// go to high while a splitHandler is handling a record at or above level, and to
+// low otherwise.
+type levelSplit struct {
+	mu    sync.Mutex
+	low   io.Writer
+	high  io.Writer
+	level slog.Level
+	cur   io.Writer
+}
+
+func (s *levelSplit) Write(p []byte) (int, error) {
+	if s.cur != nil {
+		return s.cur.Write(p)
+	}
+	return s.low.Write(p)
+}
+
+// splitHandler selects the writer of a levelSplit for each record handled by
+// inner. Records are handled one at a time.
+type splitHandler struct {
+	inner slog.Handler
+	split *levelSplit
+}
+
+func (h *splitHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.inner.Enabled(ctx, lvl)
+}
+
+func (h *splitHandler) Handle(ctx context.Context, r slog.Record) error {
+	s := h.split
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cur = s.low
+	if r.Level >= s.level {
+		s.cur = s.high
+	}
+	defer func() { s.cur = nil }()
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *splitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &splitHandler{inner: h.inner.WithAttrs(attrs), split: h.split}
+}
+
+func (h *splitHandler) WithGroup(name string) slog.Handler {
+	return &splitHandler{inner: h.inner.WithGroup(name), split: h.split}
+}
+
+// NewStdStreams returns a logger writing debug and info records to stdout and
+// warnings, errors, and fatal records to stderr, as expected by container
+// platforms that treat the two streams differently. Other options apply to both
+// streams.
+func NewStdStreams(opts ...Option) *L {
+	opts = append(opts,
+		WithDestination(os.Stdout),
+		WithLevelSplit(os.Stderr, "warn"),
+	)
+	return New(opts...)
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerLevelSplit(t *testing.T) {
+	var low, high syncBuffer
+	l := New(
+		WithDestination(&low),
+		WithLevelSplit(&high, "warn"),
+		WithLevel("debug"),
+		WithCaller(false),
+		WithDeduplication(10*time.Millisecond),
+	)
+
+	l.Debug("debug")
+	l.New("sub").Info("info")
+	require.NoError(t, l.Audit("login", "alice", "app"))
+	l.Warn("warn")
+	l.Err("err")
+	l.Err("err")
+
+	require.Eventually(t, func() bool {
+		return bytes.Contains([]byte(high.String()), []byte("repeated=1"))
+	}, time.Second, 5*time.Millisecond)
+
+	require.Contains(t, low.String(), "msg=debug")
+	require.Contains(t, low.String(), "msg=info")
+	require.Contains(t, low.String(), "msg=login")
+	require.NotContains(t, low.String(), "msg=warn")
+	require.NotContains(t, low.String(), "msg=err")
+
+	require.Contains(t, high.String(), "msg=warn")
+	require.Contains(t, high.String(), "msg=err")
+	require.NotContains(t, high.String(), "msg=info")
+	require.NotContains(t, high.String(), "msg=login")
+}
+
+func TestNewStdStreams(t *testing.T) {
+	l := NewStdStreams(WithLevel("info"))
+	h, ok := l.slogger.Handler().(*splitHandler)
+	require.True(t, ok)
+	require.Equal(t, "warn", levelLabel(h.split.level))
+}
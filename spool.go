@@ -0,0 +1,282 @@
+package logger
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrSpoolFull is returned by Spool.Write when a record can't be delivered and
+// the spool has no room left to queue it.
+var ErrSpoolFull = errors.New("logger: spool is full")
+
+// SpoolConfig configures a Spool.
+type SpoolConfig struct {
+	// Dir is the local directory records are queued in. Records left in the
+	// directory by a previous Spool, e.g. before a restart, are replayed.
+	Dir string
+
+	// MaxSize is the size in bytes of the records waiting to be replayed
+	// beyond which records are dropped. Defaults to 64MB.
+	MaxSize int64
+
+	// RetryInterval is how often delivery of queued records is retried.
+	// Defaults to 5 seconds.
+	RetryInterval time.Duration
+
+	// OnError is called whenever writing to the destination fails.
+	OnError func(error)
+}
+
+const spoolQueue = "spool.queue"
+
+// spoolFrameHeader is the size of the length prefix of each queued record.
+const spoolFrameHeader = 4
+
+// Spool is a destination that writes records to an unreliable destination,
+// such as a connection to Loki or Splunk, and queues them in a local directory
+// while the destination fails. Queued records are replayed in order once it
+// recovers; until then, new records are queued behind them. Records are
+// delivered at least once: if the process stops while replaying, a record may
+// be written again by the next Spool.
+type Spool struct {
+	w   io.Writer
+	cfg SpoolConfig
+
+	mu      sync.Mutex
+	f       *os.File
+	head    int64
+	size    int64
+	lastErr error
+
+	kick chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewSpool returns a Spool writing to w.
+func NewSpool(w io.Writer, cfg SpoolConfig) (*Spool, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("spool directory is required")
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = 64 << 20
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = 5 * time.Second
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(cfg.Dir, spoolQueue), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	s := &Spool{
+		w:    w,
+		cfg:  cfg,
+		f:    f,
+		size: fi.Size(),
+		kick: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.run()
+	if s.size > 0 {
+		s.trigger()
+	}
+
+	return s, nil
+}
+
+// Write writes p to the destination, or queues it if the destination fails or
+// earlier records are still queued.
+func (s *Spool) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return 0, os.ErrClosed
+	}
+
+	if s.head == s.size {
+		_, err := s.w.Write(p)
+		if err == nil {
+			s.lastErr = nil
+			return len(p), nil
+		}
+		s.lastErr = err
+		s.report(err)
+	}
+
+	if err := s.enqueue(p); err != nil {
+		return 0, err
+	}
+	s.trigger()
+	return len(p), nil
+}
+
+// Close stops replaying queued records. Records that haven't been delivered
+// remain in the directory.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	if s.f == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.mu.Unlock()
+
+	close(s.done)
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
+
+// Health returns the last error writing to the destination while records are
+// queued.
+func (s *Spool) Health() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.head == s.size || s.lastErr == nil {
+		return nil
+	}
+	return fmt.Errorf("%d bytes queued, last write failed: %w", s.size-s.head, s.lastErr)
+}
+
+// pending returns the number of bytes queued.
+func (s *Spool) pending() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size - s.head
+}
+
+// enqueue appends p to the queue. s.mu must be held.
+func (s *Spool) enqueue(p []byte) error {
+	if s.size-s.head+spoolFrameHeader+int64(len(p)) > s.cfg.MaxSize {
+		return ErrSpoolFull
+	}
+
+	frame := make([]byte, spoolFrameHeader+len(p))
+	binary.BigEndian.PutUint32(frame, uint32(len(p)))
+	copy(frame[spoolFrameHeader:], p)
+
+	n, err := s.f.Write(frame)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("queueing record: %w", err)
+	}
+	return nil
+}
+
+func (s *Spool) trigger() {
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Spool) run() {
+	defer s.wg.Done()
+
+	t := time.NewTicker(s.cfg.RetryInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-t.C:
+		case <-s.kick:
+		}
+		s.replay()
+	}
+}
+
+// replay writes queued records to the destination, oldest first, until the
+// queue is empty or a write fails. The lock is released between records so
+// that logging isn't blocked for the whole replay.
+func (s *Spool) replay() {
+	for {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		s.mu.Lock()
+		more, err := s.replayOne()
+		s.mu.Unlock()
+		if err != nil {
+			s.report(err)
+			return
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+// replayOne writes the oldest queued record to the destination, reporting
+// whether any records are left. s.mu must be held.
+func (s *Spool) replayOne() (bool, error) {
+	if s.f == nil || s.head == s.size {
+		return false, nil
+	}
+
+	var hdr [spoolFrameHeader]byte
+	if _, err := s.f.ReadAt(hdr[:], s.head); err != nil {
+		return false, s.discard(fmt.Errorf("reading queued record: %w", err))
+	}
+	n := int64(binary.BigEndian.Uint32(hdr[:]))
+	if s.head+spoolFrameHeader+n > s.size {
+		return false, s.discard(errors.New("reading queued record: truncated record"))
+	}
+
+	p := make([]byte, n)
+	if _, err := s.f.ReadAt(p, s.head+spoolFrameHeader); err != nil {
+		return false, s.discard(fmt.Errorf("reading queued record: %w", err))
+	}
+	if _, err := s.w.Write(p); err != nil {
+		s.lastErr = err
+		return false, err
+	}
+	s.lastErr = nil
+
+	s.head += spoolFrameHeader + n
+	if s.head < s.size {
+		return true, nil
+	}
+	s.head, s.size = 0, 0
+	return false, s.f.Truncate(0)
+}
+
+// discard empties a queue that can't be read, returning err. s.mu must be
+// held.
+func (s *Spool) discard(err error) error {
+	s.head, s.size = 0, 0
+	return errors.Join(err, s.f.Truncate(0))
+}
+
+func (s *Spool) report(err error) {
+	if err != nil && s.cfg.OnError != nil {
+		s.cfg.OnError(err)
+	}
+}
@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// flakyWriter fails writes while down is set.
+type flakyWriter struct {
+	mu   sync.Mutex
+	down bool
+	buf  bytes.Buffer
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.down {
+		return 0, errors.New("connection refused")
+	}
+	return w.buf.Write(p)
+}
+
+func (w *flakyWriter) setDown(down bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.down = down
+}
+
+func (w *flakyWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestSpool(t *testing.T) {
+	w := &flakyWriter{}
+	var mu sync.Mutex
+	var errs []error
+	s, err := NewSpool(w, SpoolConfig{
+		Dir:           t.TempDir(),
+		RetryInterval: 10 * time.Millisecond,
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	l := New(WithDestination(s), WithLevel("info"), WithCaller(false), WithFormat(FormatJSON))
+	l.Info("one")
+	w.setDown(true)
+	l.Info("two")
+	l.Info("three")
+	require.Positive(t, s.pending())
+	w.setDown(false)
+	l.Info("four")
+
+	require.Eventually(t, func() bool { return s.pending() == 0 }, time.Second, 5*time.Millisecond)
+	var msgs []string
+	for _, line := range bytes.Split(bytes.TrimSpace([]byte(w.String())), []byte("\n")) {
+		msgs = append(msgs, string(line[bytes.Index(line, []byte(`"msg"`)):]))
+	}
+	require.Equal(t, []string{
+		`"msg":"one","src":"go-logger.test"}`,
+		`"msg":"two","src":"go-logger.test"}`,
+		`"msg":"three","src":"go-logger.test"}`,
+		`"msg":"four","src":"go-logger.test"}`,
+	}, msgs)
+
+	mu.Lock()
+	require.NotEmpty(t, errs)
+	mu.Unlock()
+}
+
+func TestSpoolRestart(t *testing.T) {
+	dir := t.TempDir()
+	w := &flakyWriter{down: true}
+	s, err := NewSpool(w, SpoolConfig{Dir: dir, RetryInterval: time.Hour})
+	require.NoError(t, err)
+
+	_, err = s.Write([]byte("one\n"))
+	require.NoError(t, err)
+	_, err = s.Write([]byte("two\n"))
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+	_, err = s.Write([]byte("three\n"))
+	require.Error(t, err)
+
+	w.setDown(false)
+	s, err = NewSpool(w, SpoolConfig{Dir: dir, RetryInterval: time.Hour})
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.Eventually(t, func() bool { return s.pending() == 0 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, "one\ntwo\n", w.String())
+}
+
+func TestSpoolFull(t *testing.T) {
+	w := &flakyWriter{down: true}
+	s, err := NewSpool(w, SpoolConfig{Dir: t.TempDir(), MaxSize: 10, RetryInterval: time.Hour})
+	require.NoError(t, err)
+	defer s.Close()
+
+	_, err = s.Write([]byte("123456"))
+	require.NoError(t, err)
+	_, err = s.Write([]byte("7"))
+	require.ErrorIs(t, err, ErrSpoolFull)
+
+	t.Run("replayed", func(t *testing.T) {
+		w := &flakyWriter{down: true}
+		s, err := NewSpool(w, SpoolConfig{Dir: t.TempDir(), MaxSize: 20, RetryInterval: time.Hour})
+		require.NoError(t, err)
+		defer s.Close()
+
+		for _, p := range []string{"123456", "abcdef"} {
+			_, err = s.Write([]byte(p))
+			require.NoError(t, err)
+		}
+
+		// Records already replayed don't count towards MaxSize.
+		w.setDown(false)
+		s.mu.Lock()
+		more, err := s.replayOne()
+		s.mu.Unlock()
+		require.NoError(t, err)
+		require.True(t, more)
+		require.EqualValues(t, 10, s.pending())
+
+		_, err = s.Write([]byte("ghijk"))
+		require.NoError(t, err)
+		_, err = s.Write([]byte("l"))
+		require.ErrorIs(t, err, ErrSpoolFull)
+	})
+}
+
+func TestNewSpoolMissingDir(t *testing.T) {
+	_, err := NewSpool(&bytes.Buffer{}, SpoolConfig{})
+	require.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package logger
+
+import "strings"
+
+// sourceFilter decides which loggers are muted based on their src chain.
+type sourceFilter struct {
+	allow []string
+	deny  []string
+}
+
+// muted reports whether a logger with the given src chain is muted. The first
+// element of the chain is the application name; patterns are matched against
+// both the full chain and the chain below the application name, so db.* matches
+// myapp.db.conn.
+func (f *sourceFilter) muted(src []string) bool {
+	if f == nil {
+		return false
+	}
+
+	if len(f.allow) > 0 && !sourceMatch(f.allow, src) {
+		return true
+	}
+	return sourceMatch(f.deny, src)
+}
+
+// sourceMatch reports whether the src chain matches any of patterns, either in
+// full or below the application name.
+func sourceMatch(patterns []string, src []string) bool {
+	full := strings.Join(src, ".")
+	var rel string
+	if len(src) > 1 {
+		rel = strings.Join(src[1:], ".")
+	}
+
+	for _, p := range patterns {
+		if globMatch(p, full) || (rel != "" && globMatch(p, rel)) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch matches name against pattern, where * matches any sequence of
+// characters including dots, so db.* matches both db.conn and db.conn.pool.
+func globMatch(pattern, name string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == name
+	}
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+
+	last := len(parts) - 1
+	for _, p := range parts[1:last] {
+		i := strings.Index(name, p)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(p):]
+	}
+	return strings.HasSuffix(name, parts[last])
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"db", "db", true},
+		{"db", "db.conn", false},
+		{"db.*", "db.conn", true},
+		{"db.*", "db.conn.pool", true},
+		{"db.*", "db", false},
+		{"*.pool", "db.conn.pool", true},
+		{"db.*.pool", "db.conn.pool", true},
+		{"db.*.pool", "db.pool", false},
+		{"*", "anything", true},
+		{"a*a", "a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, globMatch(tt.pattern, tt.name))
+		})
+	}
+}
+
+func TestLoggerSourceFilter(t *testing.T) {
+	t.Run("deny", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithName("app"),
+			WithSourceFilter(nil, []string{"db.*"}),
+		)
+
+		l.Info("root")
+		db := l.New("db")
+		db.Info("db")
+		db.New("conn").Info("conn")
+		l.New("http").Info("http")
+
+		require.Contains(t, buf.String(), "msg=root")
+		require.Contains(t, buf.String(), "msg=db")
+		require.NotContains(t, buf.String(), "msg=conn")
+		require.Contains(t, buf.String(), "msg=http")
+	})
+
+	t.Run("allow", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithName("app"),
+			WithSourceFilter([]string{"app.db", "app.db.*"}, []string{"*.noisy"}),
+		)
+
+		l.Info("root")
+		db := l.New("db")
+		db.Info("db")
+		db.New("conn").Info("conn")
+		db.New("noisy").Info("noisy")
+		l.New("http").Info("http")
+		require.NoError(t, l.New("http").Audit("login", "alice", "app"))
+
+		require.NotContains(t, buf.String(), "msg=root")
+		require.Contains(t, buf.String(), "msg=db")
+		require.Contains(t, buf.String(), "msg=conn")
+		require.NotContains(t, buf.String(), "msg=noisy")
+		require.NotContains(t, buf.String(), "msg=http")
+		require.Contains(t, buf.String(), "msg=login")
+	})
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"context"
+	"expvar"
+	"io"
+	"log/slog"
+	"sync/atomic"
+)
+
+// Stats are counters describing a logger's output. They are shared by all
+// loggers derived from the same root logger.
+type Stats struct {
+	// Records is the number of records written, by level name.
+	Records map[string]uint64 `json:"records"`
+	// BytesWritten is the number of bytes written to the destination.
+	BytesWritten uint64 `json:"bytes_written"`
+	// WriteErrors is the number of failed writes to the destination.
+	WriteErrors uint64 `json:"write_errors"`
+	// Dropped is the number of records that were discarded by filters or
+	// suppressed as duplicates.
+	Dropped uint64 `json:"dropped"`
+}
+
+// statLevels are the levels records are counted by. Records are counted by
+// the highest of these at or below their level.
+var statLevels = [...]slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError, LevelFatal}
+
+type stats struct {
+	records [len(statLevels)]atomic.Uint64
+	bytes   atomic.Uint64
+	errors  atomic.Uint64
+	dropped atomic.Uint64
+	lastErr atomic.Pointer[error]
+}
+
+func (s *stats) record(lvl slog.Level) {
+	i := 0
+	for j, sl := range statLevels {
+		if lvl >= sl {
+			i = j
+		}
+	}
+	s.records[i].Add(1)
+}
+
+// setLastErr records err as the result of the last write. It is separate from
+// statsWriter.Write so that err only escapes to the heap when there is one.
+func (s *stats) setLastErr(err error) {
+	s.lastErr.Store(&err)
+}
+
+func (s *stats) drop(n int) {
+	if s != nil && n > 0 {
+		s.dropped.Add(uint64(n))
+	}
+}
+
+// Stats returns a snapshot of the logger's counters.
+func (l *L) Stats() Stats {
+	st := Stats{Records: make(map[string]uint64, len(statLevels))}
+	if l == nil || l.stats == nil {
+		return st
+	}
+	for i, lvl := range statLevels {
+		st.Records[levelLabel(lvl)] = l.stats.records[i].Load()
+	}
+	st.BytesWritten = l.stats.bytes.Load()
+	st.WriteErrors = l.stats.errors.Load()
+	st.Dropped = l.stats.dropped.Load()
+	return st
+}
+
+// StatsVar returns the logger's Stats as an expvar.Var, e.g.
+//
+//	expvar.Publish("logger", l.StatsVar())
+func (l *L) StatsVar() expvar.Var {
+	return expvar.Func(func() any { return l.Stats() })
+}
+
+// statsWriter counts the bytes and errors of writes to w.
+type statsWriter struct {
+	w     io.Writer
+	stats *stats
+}
+
+func (w *statsWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.stats.bytes.Add(uint64(n))
+	if err != nil {
+		w.stats.errors.Add(1)
+		w.stats.setLastErr(err)
+	} else if w.stats.lastErr.Load() != nil {
+		w.stats.lastErr.Store(nil)
+	}
+	return n, err
+}
+
+// statsHandler counts the records handled by inner.
+type statsHandler struct {
+	inner slog.Handler
+	stats *stats
+}
+
+func (h *statsHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.inner.Enabled(ctx, lvl)
+}
+
+func (h *statsHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.stats.record(r.Level)
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *statsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &statsHandler{inner: h.inner.WithAttrs(attrs), stats: h.stats}
+}
+
+func (h *statsHandler) WithGroup(name string) slog.Handler {
+	return &statsHandler{inner: h.inner.WithGroup(name), stats: h.stats}
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerStats(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("debug"),
+		WithCaller(false),
+		WithFilter(Not(MessageContains("noisy"))),
+		WithDeduplication(time.Hour),
+	)
+	sub := l.New("sub")
+
+	l.Debug("one")
+	sub.Info("two")
+	l.Info("three")
+	l.Info("noisy")
+	for i := 0; i < 4; i++ {
+		l.Warn("repeated")
+	}
+	sub.Err("four")
+
+	st := l.Stats()
+	require.Equal(t, map[string]uint64{"debug": 1, "info": 2, "warn": 2, "err": 1, "fatal": 0}, st.Records)
+	require.Equal(t, uint64(buf.Len()), st.BytesWritten)
+	require.Zero(t, st.WriteErrors)
+	require.Equal(t, uint64(3), st.Dropped)
+	require.Equal(t, st, sub.Stats())
+
+	var fromVar Stats
+	require.NoError(t, json.Unmarshal([]byte(l.StatsVar().String()), &fromVar))
+	require.Equal(t, st, fromVar)
+}
+
+func TestLoggerStatsWriteErrors(t *testing.T) {
+	l := New(WithDestination(&failingWriter{err: errors.New("broken pipe")}), WithLevel("info"))
+
+	l.Info("foo")
+	l.Info("bar")
+	require.Equal(t, uint64(2), l.Stats().WriteErrors)
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// loggingErrorKey is the attribute describing malformed keyvals.
+const loggingErrorKey = "logging_error"
+
+// checkKeyvals validates that keyvals consist of slog.Attr values and string keys
+// each followed by a value. If they don't, it panics or returns keyvals without
+// the malformed pairs along with a logging_error attribute identifying the call
+// site at pc, depending on the logger's configuration. The attribute is empty if
+// keyvals are well formed.
+func (l *L) checkKeyvals(keyvals []any, pc uintptr) ([]any, slog.Attr) {
+	var problem string
+	for i := 0; i < len(keyvals); i++ {
+		switch k := keyvals[i].(type) {
+		case slog.Attr:
+		case string:
+			if i == len(keyvals)-1 {
+				problem = fmt.Sprintf("missing value for key %q", k)
+			}
+			i++
+		default:
+			problem = fmt.Sprintf("key at index %d is %T, not string", i, k)
+		}
+		if problem != "" {
+			break
+		}
+	}
+
+	if problem == "" {
+		return keyvals, slog.Attr{}
+	}
+
+	msg := fmt.Sprintf("malformed keyvals at %s: %s", callerFile(callerFrame(pc), l.callerPrefixTrim), problem)
+	if l.strictPanic {
+		panic(msg)
+	}
+	return wellFormed(keyvals), slog.String(loggingErrorKey, msg)
+}
+
+// wellFormed returns keyvals without a trailing key missing its value and without
+// keys that aren't strings, along with the values following them, so that slog
+// doesn't log them as !BADKEY.
+func wellFormed(keyvals []any) []any {
+	out := make([]any, 0, len(keyvals))
+	for i := 0; i < len(keyvals); i++ {
+		switch k := keyvals[i].(type) {
+		case slog.Attr:
+			out = append(out, k)
+		case string:
+			if i+1 < len(keyvals) {
+				out = append(out, k, keyvals[i+1])
+			}
+			i++
+		default:
+			i++
+		}
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerStrictKeys(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithStrictKeys(false),
+		WithCallerPrefixTrim("github.com/jasonhancock/go-logger"),
+	)
+
+	tests := []struct {
+		desc     string
+		keyvals  []any
+		expected string
+	}{
+		{"valid", []any{"key1", "value1", slog.Int("key2", 2)}, ""},
+		{"odd", []any{"key1", "value1", "key2"}, `missing value for key \"key2\"`},
+		{"non-string key", []any{"key1", "value1", 42, "value2"}, "key at index 2 is int, not string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			defer buf.Reset()
+
+			l.Info("foo", tt.keyvals...)
+			_, _, line, _ := runtime.Caller(0)
+
+			if tt.expected == "" {
+				require.NotContains(t, buf.String(), loggingErrorKey)
+				return
+			}
+			require.Contains(t, buf.String(), fmt.Sprintf(`logging_error="malformed keyvals at strict_test.go:%d: %s"`, line-1, tt.expected))
+			require.Contains(t, buf.String(), "key1=value1")
+			require.NotContains(t, buf.String(), "!BADKEY")
+			require.NotContains(t, buf.String(), "value2")
+		})
+	}
+
+	t.Run("With", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.With("key1").Info("foo")
+		require.Contains(t, buf.String(), `logging_error="malformed keyvals at strict_test.go:`)
+		require.NotContains(t, buf.String(), "!BADKEY")
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		l := New(WithDestination(&buf), WithLevel("info"), WithStrictKeys(true))
+		_, _, line, _ := runtime.Caller(0)
+		expected := fmt.Sprintf(`malformed keyvals at github.com/jasonhancock/go-logger/strict_test.go:%d: missing value for key "key1"`, line+3)
+		require.PanicsWithValue(t, expected, func() {
+			l.Info("foo", "key1")
+		})
+	})
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// tee is an additional destination records are written to in their own format.
+type tee struct {
+	w      io.Writer
+	format string
+}
+
+// fanoutHandler passes records to several handlers. The first handler decides
+// whether a level is enabled; the others are expected to accept everything.
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+func (h *fanoutHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.handlers[0].Enabled(ctx, lvl)
+}
+
+func (h *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, hh := range h.handlers {
+		errs = append(errs, hh.Handle(ctx, r.Clone()))
+	}
+	return errors.Join(errs...)
+}
+
+func (h *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := &fanoutHandler{handlers: make([]slog.Handler, len(h.handlers))}
+	for i, hh := range h.handlers {
+		c.handlers[i] = hh.WithAttrs(attrs)
+	}
+	return c
+}
+
+func (h *fanoutHandler) WithGroup(name string) slog.Handler {
+	c := &fanoutHandler{handlers: make([]slog.Handler, len(h.handlers))}
+	for i, hh := range h.handlers {
+		c.handlers[i] = hh.WithGroup(name)
+	}
+	return c
+}
+
+// teeHandler returns h followed by a handler for each tee, all sharing opts
+// except for the level, which is left to h, and the handlers passed to
+// WithHandler.
+func (o *options) teeHandler(h slog.Handler, opts slog.HandlerOptions, keys KeyNames) slog.Handler {
+	tees := o.teeHandlers(opts, keys)
+	if len(tees) == 0 {
+		return h
+	}
+	return &fanoutHandler{handlers: append([]slog.Handler{h}, tees...)}
+}
+
+// teeHandlers returns a handler for each tee, sharing opts except for the level,
+// followed by the handlers passed to WithHandler.
+func (o *options) teeHandlers(opts slog.HandlerOptions, keys KeyNames) []slog.Handler {
+	opts.Level = LevelAll
+	var handlers []slog.Handler
+	for _, t := range o.tees {
+		w := t.w
+		if strings.ToLower(t.format) == FormatCloudEvents {
+			w = o.cloudEventsWriter(w, keys)
+		}
+		handlers = append(handlers, newFormatHandler(t.format, w, &opts))
+	}
+	return append(handlers, o.handlers...)
+}
+
+// NewDual returns a logger writing human readable logfmt to console, colored
+// when it is a terminal, and JSON to machine, such as a file shipped by an
+// agent. Other options apply to both outputs.
+func NewDual(console, machine io.Writer, opts ...Option) *L {
+	opts = append(opts,
+		WithDestination(console),
+		WithFormat(FormatLogFmt),
+		WithColor(ColorAuto),
+		WithTee(machine, FormatJSON),
+	)
+	return New(opts...)
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDual(t *testing.T) {
+	var console, machine bytes.Buffer
+	l := NewDual(&console, &machine, WithLevel("info"), WithCaller(false), WithName("app"))
+
+	l.Debug("dropped")
+	l.New("db").Info("foo", "key1", "value1")
+	require.NoError(t, l.Audit("login", "alice", "app"))
+
+	require.NotContains(t, console.String(), "dropped")
+	require.NotContains(t, machine.String(), "dropped")
+	require.Contains(t, console.String(), "msg=foo src=app src=app.db key1=value1\n")
+	require.Contains(t, console.String(), "level=audit msg=login")
+
+	dec := json.NewDecoder(&machine)
+	var rec map[string]any
+	require.NoError(t, dec.Decode(&rec))
+	require.Equal(t, "foo", rec["msg"])
+	require.Equal(t, "app.db", rec["src"])
+	require.Equal(t, "value1", rec["key1"])
+	require.NoError(t, dec.Decode(&rec))
+	require.Equal(t, "audit", rec["level"])
+}
+
+func TestLoggerTeeLevelChange(t *testing.T) {
+	var console, machine bytes.Buffer
+	l := New(
+		WithDestination(&console),
+		WithLevel("info"),
+		WithTee(&machine, FormatJSON),
+	)
+
+	o := l.TemporaryLevel("debug", time.Hour)
+	l.Debug("elevated")
+	o.Revert()
+	l.Debug("dropped")
+
+	require.Contains(t, console.String(), "msg=elevated")
+	require.Contains(t, machine.String(), `"msg":"elevated"`)
+	require.NotContains(t, machine.String(), "dropped")
+}
+
+func TestLoggerWithHandler(t *testing.T) {
+	var buf, other bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithCaller(false),
+		WithName("app"),
+		WithHandler(slog.NewJSONHandler(&other, &slog.HandlerOptions{
+			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		})),
+	)
+
+	l.Debug("dropped")
+	l.With("key1", "value1").Warn("foo", "n", 1)
+
+	require.Contains(t, buf.String(), "msg=foo src=app key1=value1 n=1\n")
+	require.Equal(t, `{"level":"WARN","msg":"foo","src":"app","key1":"value1","n":1}`+"\n", other.String())
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// DebugT logs a message at the debug level built from a template as described
+// for InfoT
+func (l *L) DebugT(template string, keyvals ...any) {
+	if l.enabled(context.Background(), slog.LevelDebug) {
+		l.log(context.Background(), slog.LevelDebug, interpolate(template, keyvals), keyvals...)
+	}
+}
+
+// InfoT logs a message at the info level built from a template by replacing
+// each named placeholder with the value of the attribute of the same name in
+// keyvals. The attributes are logged too, so
+//
+//	l.InfoT("user {user_id} purchased {sku}", "user_id", 42, "sku", "A-1")
+//
+// logs msg="user 42 purchased A-1" user_id=42 sku=A-1. Placeholders without a
+// matching attribute are left as they are, and {{ and }} stand for literal
+// braces.
+func (l *L) InfoT(template string, keyvals ...any) {
+	if l.enabled(context.Background(), slog.LevelInfo) {
+		l.log(context.Background(), slog.LevelInfo, interpolate(template, keyvals), keyvals...)
+	}
+}
+
+// WarnT logs a message at the warning level built from a template as described
+// for InfoT
+func (l *L) WarnT(template string, keyvals ...any) {
+	if l.enabled(context.Background(), slog.LevelWarn) {
+		l.log(context.Background(), slog.LevelWarn, interpolate(template, keyvals), keyvals...)
+	}
+}
+
+// ErrT logs a message at the error level built from a template as described
+// for InfoT
+func (l *L) ErrT(template string, keyvals ...any) {
+	if l.enabled(context.Background(), slog.LevelError) {
+		l.log(context.Background(), slog.LevelError, interpolate(template, keyvals), keyvals...)
+	}
+}
+
+// interpolate replaces the placeholders in template with the values of the
+// matching attributes in keyvals.
+func interpolate(template string, keyvals []any) string {
+	if !strings.ContainsAny(template, "{}") {
+		return template
+	}
+
+	attrs := toAttrs(keyvals)
+	var b strings.Builder
+	b.Grow(len(template))
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		switch {
+		case (c == '{' || c == '}') && i+1 < len(template) && template[i+1] == c:
+			b.WriteByte(c)
+			i++
+		case c == '{':
+			end := strings.IndexByte(template[i+1:], '}')
+			if end == -1 {
+				b.WriteString(template[i:])
+				return b.String()
+			}
+			name := template[i+1 : i+1+end]
+			if v, ok := findAttr(attrs, name); ok {
+				b.WriteString(v.String())
+			} else {
+				b.WriteString(template[i : i+end+2])
+			}
+			i += end + 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// findAttr returns the resolved value of the first attribute named key.
+func findAttr(attrs []slog.Attr, key string) (slog.Value, bool) {
+	for _, a := range attrs {
+		if a.Key == key {
+			return a.Value.Resolve(), true
+		}
+	}
+	return slog.Value{}, false
+}
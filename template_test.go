@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolate(t *testing.T) {
+	tests := []struct {
+		template string
+		keyvals  []any
+		expected string
+	}{
+		{"no placeholders", []any{"a", 1}, "no placeholders"},
+		{"user {user_id} purchased {sku}", []any{"user_id", 42, "sku", "A-1"}, "user 42 purchased A-1"},
+		{"{a}{a}", []any{"a", "x"}, "xx"},
+		{"took {elapsed}", []any{slog.Duration("elapsed", time.Second)}, "took 1s"},
+		{"missing {b}", []any{"a", 1}, "missing {b}"},
+		{"unterminated {a", []any{"a", 1}, "unterminated {a"},
+		{"literal {{a}} and }}", []any{"a", 1}, "literal {a} and }"},
+		{"first {a}", []any{"a", 1, "a", 2}, "first 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			require.Equal(t, tt.expected, interpolate(tt.template, tt.keyvals))
+		})
+	}
+}
+
+func TestLoggerTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("info"), WithKeyNames(KeyNames{Time: KeyOmit}))
+
+	l.DebugT("dropped {a}", "a", 1)
+	l.InfoT("user {user_id} purchased {sku}", "user_id", 42, "sku", "A-1")
+	_, _, line, _ := runtime.Caller(0)
+	l.WarnT("warn {a}", "a", 1)
+	l.ErrT("err {a}", "a", 1)
+
+	require.Equal(t, fmt.Sprintf(`level=info msg="user 42 purchased A-1" src=go-logger.test user_id=42 sku=A-1 caller=github.com/jasonhancock/go-logger/template_test.go:%d
+level=warn msg="warn 1" src=go-logger.test a=1 caller=github.com/jasonhancock/go-logger/template_test.go:%d
+level=err msg="err 1" src=go-logger.test a=1 caller=github.com/jasonhancock/go-logger/template_test.go:%d
+`, line-1, line+1, line+2), buf.String())
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// NewTesting returns a logger that sends each record to t.Log so that output is
+// associated with the test that produced it and only shown on failure or when
+// running with -v. The caller attribute continues to point at the code under
+// test. Where the testing package supports it, records are written without the
+// file:line prefix t.Log adds, which would otherwise point into the logger.
+// Records logged after the test has completed are discarded.
+func NewTesting(t testing.TB, opts ...Option) *L {
+	w := &testWriter{t: t}
+	t.Cleanup(w.close)
+
+	return New(append([]Option{
+		WithDestination(w),
+		WithName(t.Name()),
+	}, opts...)...)
+}
+
+// testWriter is an io.Writer that forwards each write to the test's output.
+type testWriter struct {
+	mu   sync.Mutex
+	t    testing.TB
+	done bool
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.done {
+		return len(p), nil
+	}
+
+	// testing.TB gained Output in Go 1.25.
+	if o, ok := w.t.(interface{ Output() io.Writer }); ok {
+		return o.Output().Write(p)
+	}
+
+	w.t.Helper()
+	w.t.Log(strings.TrimSuffix(string(p), "\n"))
+
+	return len(p), nil
+}
+
+func (w *testWriter) close() {
+	w.mu.Lock()
+	w.done = true
+	w.mu.Unlock()
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTesting(t *testing.T) {
+	tb := &recordingTB{TB: t}
+
+	l := NewTesting(tb, WithLevel("info"))
+	l.Info("foo", "key1", "value1")
+	l.Debug("filtered")
+
+	require.Len(t, tb.lines, 1)
+	require.Contains(t, tb.lines[0], "msg=foo")
+	require.Contains(t, tb.lines[0], "key1=value1")
+	require.Contains(t, tb.lines[0], "src="+t.Name())
+	require.Contains(t, tb.lines[0], "caller=github.com/jasonhancock/go-logger/testing_test.go")
+	require.NotContains(t, tb.lines[0], "\n")
+
+	tb.cleanup()
+	l.Info("after completion")
+	require.Len(t, tb.lines, 1)
+}
+
+func TestNewTestingPrefix(t *testing.T) {
+	if os.Getenv("GO_LOGGER_TESTING_CHILD") != "" {
+		NewTesting(t, WithLevel("info"), WithKeyNames(KeyNames{Time: KeyOmit})).Info("from child")
+		return
+	}
+	if _, ok := any(t).(interface{ Output() io.Writer }); !ok {
+		t.Skip("testing.T has no Output method")
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestNewTestingPrefix$", "-test.v")
+	cmd.Env = append(os.Environ(), "GO_LOGGER_TESTING_CHILD=1")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Regexp(t, `(?m)^\s+level=info msg="from child" src=TestNewTestingPrefix caller=github.com/jasonhancock/go-logger/testing_test.go:\d+$`, string(out))
+	require.NotContains(t, string(out), "stats.go")
+}
+
+type recordingTB struct {
+	testing.TB
+	lines    []string
+	cleanups []func()
+}
+
+func (r *recordingTB) Log(args ...any) {
+	for _, a := range args {
+		r.lines = append(r.lines, a.(string))
+	}
+}
+
+func (r *recordingTB) Output() io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		r.lines = append(r.lines, strings.TrimSuffix(string(p), "\n"))
+		return len(p), nil
+	})
+}
+
+func (r *recordingTB) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func (r *recordingTB) cleanup() {
+	for _, f := range r.cleanups {
+		f()
+	}
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
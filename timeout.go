@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrWriteTimeout is reported to the error handler when a record is dropped
+// because the destination didn't accept it within the timeout set by
+// WithWriteTimeout.
+var ErrWriteTimeout = errors.New("logger: write to destination timed out")
+
+// ErrWriteDelayed is reported to the error handler when the destination didn't
+// finish writing a record within the timeout set by WithWriteTimeout. The record
+// is still written once the destination unblocks, so it isn't counted as dropped
+// or written to the fallback destination.
+var ErrWriteDelayed = errors.New("logger: write to destination delayed")
+
+// errTimeoutWriterClosed is returned when writing to a timeoutWriter after the
+// logger was closed.
+var errTimeoutWriterClosed = errors.New("logger: write to closed destination")
+
+// timeoutWriter hands writes to a goroutine writing to w, giving up on records
+// that aren't written within timeout so that a stalled destination can't block
+// the application. Only one write to w is in progress at a time.
+type timeoutWriter struct {
+	w       io.Writer
+	timeout time.Duration
+	stats   *stats
+
+	reqs      chan *writeReq
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type writeReq struct {
+	buf *bytes.Buffer
+	res chan error
+}
+
+func newTimeoutWriter(w io.Writer, timeout time.Duration, st *stats) *timeoutWriter {
+	tw := &timeoutWriter{
+		w:       w,
+		timeout: timeout,
+		stats:   st,
+		reqs:    make(chan *writeReq),
+		done:    make(chan struct{}),
+	}
+	go tw.run()
+	return tw
+}
+
+func (w *timeoutWriter) run() {
+	for {
+		select {
+		case req := <-w.reqs:
+			_, err := w.w.Write(req.buf.Bytes())
+			putBuffer(req.buf)
+			req.res <- err
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// Write returns ErrWriteTimeout if p couldn't be handed to the destination
+// within the timeout, and ErrWriteDelayed if the destination didn't finish
+// writing it within the timeout, in which case p is still written once the
+// destination unblocks.
+func (w *timeoutWriter) Write(p []byte) (int, error) {
+	t := time.NewTimer(w.timeout)
+	defer t.Stop()
+
+	// The caller may reuse p once Write returns, even if it is yet to be written.
+	buf := getBuffer()
+	buf.Write(p)
+	req := &writeReq{buf: buf, res: make(chan error, 1)}
+
+	select {
+	case w.reqs <- req:
+	case <-t.C:
+		putBuffer(buf)
+		w.stats.drop(1)
+		return 0, ErrWriteTimeout
+	case <-w.done:
+		putBuffer(buf)
+		return 0, errTimeoutWriterClosed
+	}
+
+	select {
+	case err := <-req.res:
+		if err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	case <-t.C:
+		return len(p), ErrWriteDelayed
+	}
+}
+
+// Close stops the goroutine writing to the destination. A write in progress is
+// abandoned.
+func (w *timeoutWriter) Close() error {
+	w.closeOnce.Do(func() { close(w.done) })
+	return nil
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// stallWriter blocks writes until release is closed.
+type stallWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func (w *stallWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *stallWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestLoggerWriteTimeout(t *testing.T) {
+	w := &stallWriter{release: make(chan struct{})}
+	var fallback syncBuffer
+	var mu sync.Mutex
+	var errs []error
+	l := New(
+		WithDestination(w),
+		WithLevel("info"),
+		WithCaller(false),
+		WithWriteTimeout(20*time.Millisecond),
+		WithFallbackDestination(&fallback),
+		WithErrorHandler(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}),
+	)
+	defer l.Close()
+
+	start := time.Now()
+	l.Info("handed off")
+	l.Info("not accepted")
+	require.Less(t, time.Since(start), time.Second)
+
+	// The first record is delayed rather than dropped.
+	require.Equal(t, uint64(1), l.Stats().Dropped)
+	mu.Lock()
+	require.Len(t, errs, 2)
+	require.ErrorIs(t, errs[0], ErrWriteDelayed)
+	require.ErrorIs(t, errs[1], ErrWriteTimeout)
+	mu.Unlock()
+	require.NotContains(t, fallback.String(), "msg=\"handed off\"")
+	require.Contains(t, fallback.String(), "msg=\"not accepted\"")
+
+	close(w.release)
+	l.Info("recovered")
+	require.Equal(t, 1, strings.Count(w.String(), "msg=\"handed off\""))
+	require.NotContains(t, w.String(), "msg=\"not accepted\"")
+	require.Contains(t, w.String(), "msg=recovered")
+	require.Equal(t, uint64(1), l.Stats().Dropped)
+}
+
+func TestTimeoutWriterClose(t *testing.T) {
+	var buf bytes.Buffer
+	w := newTimeoutWriter(&buf, time.Second, &stats{})
+	_, err := w.Write([]byte("foo"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+
+	_, err = w.Write([]byte("bar"))
+	require.ErrorIs(t, err, errTimeoutWriterClosed)
+	require.Equal(t, "foo", buf.String())
+}
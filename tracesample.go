@@ -0,0 +1,25 @@
+package logger
+
+import "context"
+
+type traceSampledKey struct{}
+
+// ContextWithTraceSampled returns a copy of ctx recording whether the trace it
+// belongs to is sampled, for WithTraceSampling. Middleware sets it from the
+// sampled flag of the traceparent header.
+func ContextWithTraceSampled(ctx context.Context, sampled bool) context.Context {
+	return context.WithValue(ctx, traceSampledKey{}, sampled)
+}
+
+// TraceSampled reports whether ctx belongs to a sampled trace, as recorded by
+// ContextWithTraceSampled.
+func TraceSampled(ctx context.Context) bool {
+	sampled, _ := ctx.Value(traceSampledKey{}).(bool)
+	return sampled
+}
+
+// traceForced reports whether a record logged with ctx is logged regardless of
+// the level and sampling, because its trace is sampled.
+func (l *L) traceForced(ctx context.Context) bool {
+	return l != nil && !l.muted && l.traceSampled != nil && ctx != nil && l.traceSampled(ctx)
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceSampled(t *testing.T) {
+	ctx := context.Background()
+	require.False(t, TraceSampled(ctx))
+	require.True(t, TraceSampled(ContextWithTraceSampled(ctx, true)))
+	require.False(t, TraceSampled(ContextWithTraceSampled(ctx, false)))
+}
+
+func TestWithTraceSampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithCaller(false),
+		WithSampling(map[string]float64{"info": 0}),
+		WithAdaptiveSampling(1, time.Hour),
+		WithTraceSampling(nil),
+	)
+
+	sampled := ContextWithTraceSampled(context.Background(), true)
+	unsampled := ContextWithTraceSampled(context.Background(), false)
+	for i := 0; i < 3; i++ {
+		l.DebugCtx(sampled, "sampled debug")
+		l.InfoCtx(sampled, "sampled info")
+		l.DebugCtx(unsampled, "unsampled debug")
+		l.InfoCtx(unsampled, "unsampled info")
+		l.Debug("debug")
+		l.Info("info")
+	}
+
+	require.Equal(t, 3, strings.Count(buf.String(), "msg=\"sampled debug\""))
+	require.Equal(t, 3, strings.Count(buf.String(), "msg=\"sampled info\""))
+	require.NotContains(t, buf.String(), "unsampled")
+	require.NotContains(t, buf.String(), "msg=debug")
+	require.NotContains(t, buf.String(), "msg=info")
+
+	t.Run("muted", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithName("app"),
+			WithSourceFilter(nil, []string{"db"}),
+			WithTraceSampling(nil),
+		)
+		l.New("db").DebugCtx(sampled, "muted")
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		type key struct{}
+		buf.Reset()
+		l := New(
+			WithDestination(&buf),
+			WithLevel("info"),
+			WithCaller(false),
+			WithName("app"),
+			WithKeyNames(KeyNames{Time: KeyOmit}),
+			WithTraceSampling(func(ctx context.Context) bool { return ctx.Value(key{}) != nil }),
+		)
+		l.DebugCtx(context.WithValue(context.Background(), key{}, 1), "custom")
+		l.DebugCtx(sampled, "default")
+		require.Equal(t, "level=debug msg=custom src=app\n", buf.String())
+	})
+}
+
+func TestWithTraceSamplingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithDestination(&buf), WithLevel("info"), WithCaller(false), WithTraceSampling(nil))
+
+	h := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).DebugCtx(r.Context(), "details")
+	}))
+
+	for _, flags := range []string{"01", "00"} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-"+flags)
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, 1, strings.Count(buf.String(), "msg=details"))
+}
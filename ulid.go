@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"crypto/rand"
+	"time"
+)
+
+// recordIDKey is the attribute added by WithRecordIDs.
+const recordIDKey = "record_id"
+
+// crockford is the Crockford base32 alphabet used to encode ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID returns a ULID (https://github.com/ulid/spec) for t: 48 bits of
+// milliseconds since the Unix epoch followed by 80 random bits, encoded as 26
+// characters of Crockford base32.
+func newULID(t time.Time) string {
+	var id [16]byte
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		id[i] = byte(ms)
+		ms >>= 8
+	}
+	_, _ = rand.Read(id[6:])
+
+	// 128 bits encode to 26 characters of 5 bits each, with the first character
+	// holding only the top 3 bits.
+	var s [26]byte
+	var acc uint32
+	var bits uint
+	n := len(s) - 1
+	for i := len(id) - 1; i >= 0; i-- {
+		acc |= uint32(id[i]) << bits
+		bits += 8
+		for bits >= 5 {
+			s[n] = crockford[acc&0x1f]
+			n--
+			acc >>= 5
+			bits -= 5
+		}
+	}
+	s[0] = crockford[acc&0x1f]
+	return string(s[:])
+}
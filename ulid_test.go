@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewULID(t *testing.T) {
+	ts := time.UnixMilli(1469918176385)
+
+	id := newULID(ts)
+	require.Len(t, id, 26)
+	// The timestamp portion of the example in github.com/oklog/ulid's README.
+	require.Equal(t, "01ARYZ6S41", id[:10])
+	require.Regexp(t, `^[0-9A-HJKMNP-TV-Z]{26}$`, id)
+	require.NotEqual(t, id, newULID(ts))
+	require.Less(t, id, newULID(ts.Add(time.Millisecond)))
+}
+
+func TestLoggerRecordIDs(t *testing.T) {
+	var buf bytes.Buffer
+	ts := time.Unix(0, 0)
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithCaller(false),
+		WithKeyNames(KeyNames{Time: KeyOmit}),
+		WithRecordIDs(),
+		WithDeduplication(time.Minute),
+		WithClock(func() time.Time { return ts }),
+	)
+
+	l.Info("foo")
+	l.Info("foo")
+	l.Info("bar")
+
+	re := regexp.MustCompile(`record_id=(0000000000[0-9A-Z]{16})`)
+	ids := re.FindAllStringSubmatch(buf.String(), -1)
+	require.Len(t, ids, 3, buf.String())
+	require.Contains(t, buf.String(), "repeated=1")
+	require.NotEqual(t, ids[0][1], ids[2][1])
+}
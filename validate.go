@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// validate returns an error describing every invalid option.
+func (o *options) validate() error {
+	var errs []error
+
+	if o.destination == nil {
+		errs = append(errs, errors.New("logger: destination is nil"))
+	}
+	if o.format != "" && !validFormat(o.format) {
+		errs = append(errs, fmt.Errorf("logger: unknown format %q, expected one of %s", o.format, strings.Join(AvailableFormats, ", ")))
+	}
+	for _, t := range o.tees {
+		if !validFormat(t.format) {
+			errs = append(errs, fmt.Errorf("logger: unknown tee format %q, expected one of %s", t.format, strings.Join(AvailableFormats, ", ")))
+		}
+	}
+	for _, s := range o.namedSinks {
+		if s.w == nil {
+			errs = append(errs, fmt.Errorf("logger: sink %q destination is nil", s.name))
+		}
+		if !validFormat(s.format) {
+			errs = append(errs, fmt.Errorf("logger: unknown format %q for sink %q, expected one of %s", s.format, s.name, strings.Join(AvailableFormats, ", ")))
+		}
+	}
+
+	if o.level != "" && !validLevel(o.level) {
+		errs = append(errs, unknownLevelError("level", o.level))
+	}
+	if !validLevel(o.callerLevel) {
+		errs = append(errs, unknownLevelError("caller level", o.callerLevel))
+	}
+	if o.splitDest != nil && !validLevel(o.splitLevel) {
+		errs = append(errs, unknownLevelError("split level", o.splitLevel))
+	}
+
+	for _, r := range o.routes {
+		if !slices.ContainsFunc(o.namedSinks, func(s namedSink) bool { return s.name == r.Sink }) {
+			errs = append(errs, fmt.Errorf("logger: route to unknown sink %q", r.Sink))
+		}
+		if r.Level != "" && !validLevel(r.Level) {
+			errs = append(errs, fmt.Errorf("logger: unknown level %q for route to sink %q, expected one of %s", r.Level, r.Sink, strings.Join(LevelNames(), ", ")))
+		}
+	}
+
+	for name, rate := range o.sampleRates {
+		if !validLevel(name) {
+			errs = append(errs, unknownLevelError("sampling level", name))
+		}
+		if rate < 0 || rate > 1 {
+			errs = append(errs, fmt.Errorf("logger: sampling rate %v for level %q is not between 0 and 1", rate, name))
+		}
+	}
+
+	if o.debugHeader != "" && o.debugAuthorize == nil {
+		errs = append(errs, fmt.Errorf("logger: debug header %q requires an authorize function", o.debugHeader))
+	}
+	if o.timeLocationErr != nil {
+		errs = append(errs, o.timeLocationErr)
+	}
+
+	if o.compression != "" && !strings.EqualFold(o.compression, CompressionGzip) {
+		errs = append(errs, fmt.Errorf("logger: unsupported compression %q", o.compression))
+	}
+	if o.encryptionKey != nil {
+		if _, err := newGCM(o.encryptionKey); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if o.sentryDSN != "" {
+		if _, _, err := parseSentryDSN(o.sentryDSN); err != nil {
+			errs = append(errs, fmt.Errorf("logger: %w", err))
+		}
+	}
+
+	if !isLogfmt(o.format) {
+		if o.color == ColorAlways {
+			errs = append(errs, fmt.Errorf("logger: color requires the %s format", FormatLogFmt))
+		}
+		if o.multiline {
+			errs = append(errs, fmt.Errorf("logger: multiline values require the %s format", FormatLogFmt))
+		}
+		if len(o.keyOrder) > 0 {
+			errs = append(errs, fmt.Errorf("logger: key order requires the %s format", FormatLogFmt))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validFormat reports whether format is one of AvailableFormats.
+func validFormat(format string) bool {
+	for _, f := range AvailableFormats {
+		if strings.EqualFold(format, f) {
+			return true
+		}
+	}
+	return false
+}
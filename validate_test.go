@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewE(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		errs []string
+	}{
+		{"defaults", nil, nil},
+		{"valid", []Option{WithFormat("JSON"), WithLevel("warn"), WithCallerMinLevel("err"), WithCompression("gzip"), WithEncryption(make([]byte, 32))}, nil},
+		{"level prefix", []Option{WithLevel("inf")}, nil},
+		{"format", []Option{WithFormat("xml")}, []string{`logger: unknown format "xml", expected one of logfmt, json, json-pretty, msgpack, cloudevents`}},
+		{"tee format", []Option{WithTee(&bytes.Buffer{}, "yaml")}, []string{`logger: unknown tee format "yaml"`}},
+		{"level", []Option{WithLevel("verbose")}, []string{`logger: unknown level "verbose"`}},
+		{"caller level", []Option{WithCallerMinLevel("loud")}, []string{`logger: unknown caller level "loud"`}},
+		{"split level", []Option{WithLevelSplit(&bytes.Buffer{}, "")}, []string{`logger: unknown split level ""`}},
+		{"sink", []Option{WithNamedSink("audit", nil, "csv")}, []string{`logger: sink "audit" destination is nil`, `logger: unknown format "csv" for sink "audit"`}},
+		{"route", []Option{WithRoute(Route{Sink: "audit", Level: "loud"})}, []string{`logger: route to unknown sink "audit"`, `logger: unknown level "loud" for route to sink "audit"`}},
+		{"debug header", []Option{WithDebugHeader("X-Debug-Log", nil)}, []string{`logger: debug header "X-Debug-Log" requires an authorize function`}},
+		{"destination", []Option{WithDestination(nil)}, []string{"logger: destination is nil"}},
+		{"compression", []Option{WithCompression("zstd")}, []string{`logger: unsupported compression "zstd"`}},
+		{"encryption", []Option{WithEncryption([]byte("short"))}, []string{"logger: invalid encryption key"}},
+		{"sentry", []Option{WithSentry("https://sentry.example.com/1")}, []string{"logger: parsing sentry dsn: missing public key"}},
+		{
+			"logfmt only",
+			[]Option{WithFormat(FormatJSON), WithColor(ColorAlways), WithMultilineValues(10), WithKeyOrder("msg")},
+			[]string{"logger: color requires the logfmt format", "logger: multiline values require the logfmt format", "logger: key order requires the logfmt format"},
+		},
+		{"multiple", []Option{WithFormat("xml"), WithLevel("verbose")}, []string{"unknown format", "unknown level"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewE(append([]Option{WithDestination(&bytes.Buffer{})}, tt.opts...)...)
+			if len(tt.errs) == 0 {
+				require.NoError(t, err)
+				require.NotNil(t, l)
+				return
+			}
+			require.Error(t, err)
+			require.Nil(t, l)
+			for _, e := range tt.errs {
+				require.Contains(t, err.Error(), e)
+			}
+		})
+	}
+}
+
+func TestMustNew(t *testing.T) {
+	require.NotNil(t, MustNew(WithDestination(&bytes.Buffer{})))
+	require.Panics(t, func() { MustNew(WithFormat("xml")) })
+}
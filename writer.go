@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+// fallbackWriter writes to dest, reporting any failure to onError and retrying
+// the write on fallback if one is set, unless the write was only delayed.
+type fallbackWriter struct {
+	dest     io.Writer
+	fallback io.Writer
+	onError  func(error)
+}
+
+func (w *fallbackWriter) Write(p []byte) (int, error) {
+	n, err := w.dest.Write(p)
+	if err == nil {
+		return n, nil
+	}
+	w.reportError(err)
+
+	// A delayed record is still written to dest.
+	if w.fallback == nil || errors.Is(err, ErrWriteDelayed) {
+		return n, err
+	}
+
+	n, err = w.fallback.Write(p)
+	if err != nil {
+		w.reportError(err)
+	}
+	return n, err
+}
+
+func (w *fallbackWriter) reportError(err error) {
+	if w.onError != nil {
+		w.onError(err)
+	}
+}
+
+// indentWriter re-indents each JSON record written to it before writing it to w.
+type indentWriter struct {
+	w io.Writer
+}
+
+func (w *indentWriter) Write(p []byte) (int, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := json.Indent(buf, p, "", "  "); err != nil {
+		return w.w.Write(p)
+	}
+
+	if _, err := w.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// lockedWriter serializes writes to w.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerWriteErrors(t *testing.T) {
+	errWrite := errors.New("broken pipe")
+
+	t.Run("error handler", func(t *testing.T) {
+		var errs []error
+		l := New(
+			WithDestination(&failingWriter{err: errWrite}),
+			WithLevel("info"),
+			WithErrorHandler(func(err error) { errs = append(errs, err) }),
+		)
+
+		l.Info("foo")
+		require.Equal(t, []error{errWrite}, errs)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		var errs []error
+		var buf bytes.Buffer
+		l := New(
+			WithDestination(&failingWriter{err: errWrite}),
+			WithLevel("info"),
+			WithErrorHandler(func(err error) { errs = append(errs, err) }),
+			WithFallbackDestination(&buf),
+		)
+
+		l.Info("foo")
+		require.Equal(t, []error{errWrite}, errs)
+		require.Contains(t, buf.String(), "msg=foo")
+	})
+
+	t.Run("fallback fails", func(t *testing.T) {
+		errFallback := errors.New("disk full")
+		var errs []error
+		l := New(
+			WithDestination(&failingWriter{err: errWrite}),
+			WithLevel("info"),
+			WithErrorHandler(func(err error) { errs = append(errs, err) }),
+			WithFallbackDestination(&failingWriter{err: errFallback}),
+		)
+
+		l.Info("foo")
+		require.Equal(t, []error{errWrite, errFallback}, errs)
+	})
+}
+
+func TestLoggerJSONPretty(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(
+		WithDestination(&buf),
+		WithLevel("info"),
+		WithFormat(FormatJSONPretty),
+		WithCaller(false),
+	)
+
+	l.Info("foo", "key1", "value1")
+	l.Info("bar")
+
+	require.Contains(t, buf.String(), "{\n  \"ts\": ")
+	require.Contains(t, buf.String(), "\n  \"key1\": \"value1\"\n}\n{\n")
+
+	dec := json.NewDecoder(&buf)
+	for _, msg := range []string{"foo", "bar"} {
+		var data map[string]string
+		require.NoError(t, dec.Decode(&data))
+		require.Equal(t, msg, data["msg"])
+	}
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+// overlapWriter records whether Write was ever called concurrently.
+type overlapWriter struct {
+	inFlight atomic.Int32
+	overlap  atomic.Bool
+}
+
+func (w *overlapWriter) Write(p []byte) (int, error) {
+	if w.inFlight.Add(1) > 1 {
+		w.overlap.Store(true)
+	}
+	runtime.Gosched()
+	w.inFlight.Add(-1)
+	return len(p), nil
+}
+
+func TestLoggerSerializedWrites(t *testing.T) {
+	w := &overlapWriter{}
+	l := New(WithDestination(w), WithLevel("info"), WithSerializedWrites())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				l.Info("foo")
+				_ = l.Audit("login", "alice", "app")
+			}
+		}()
+	}
+	wg.Wait()
+	require.False(t, w.overlap.Load())
+}